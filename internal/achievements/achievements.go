@@ -0,0 +1,103 @@
+package achievements
+
+// Progress is what the rules know about a user when their submissions change
+type Progress struct {
+	TotalSolved      int
+	CurrentStreak    int
+	LongestStreak    int
+	FirstSolves      int // Days on which the user was the first verified solver
+	CategoriesSolved int
+	TotalCategories  int // Categories of the daily challenges posted so far
+	FastHardSolves   int // Hard problems solved within an hour of the post
+}
+
+// Achievement is a badge that is unlocked once its rule holds
+type Achievement struct {
+	ID          string
+	Emoji       string
+	Name        string
+	Description string
+	rule        func(p *Progress) bool
+}
+
+// Title returns the emoji and name of the achievement
+func (a Achievement) Title() string {
+	return a.Emoji + " " + a.Name
+}
+
+// All lists every achievement in the order they are shown. Achievements are stored by
+// ID, so new ones only need an entry here. Never change the ID of an existing one.
+var All = []Achievement{
+	{
+		ID:          "first_solve",
+		Emoji:       "🌱",
+		Name:        "First Steps",
+		Description: "Solve your first daily challenge",
+		rule:        func(p *Progress) bool { return p.TotalSolved >= 1 },
+	},
+	{
+		ID:          "streak_5",
+		Emoji:       "🔥",
+		Name:        "On Fire",
+		Description: "Solve 5 daily challenges in a row",
+		rule:        func(p *Progress) bool { return p.LongestStreak >= 5 },
+	},
+	{
+		ID:          "streak_10",
+		Emoji:       "💪",
+		Name:        "Unstoppable",
+		Description: "Solve 10 daily challenges in a row",
+		rule:        func(p *Progress) bool { return p.LongestStreak >= 10 },
+	},
+	{
+		ID:          "streak_30",
+		Emoji:       "👑",
+		Name:        "Legend",
+		Description: "Solve 30 daily challenges in a row",
+		rule:        func(p *Progress) bool { return p.LongestStreak >= 30 },
+	},
+	{
+		ID:          "first_solver_5",
+		Emoji:       "⚡",
+		Name:        "Early Bird",
+		Description: "Be the first verified solver of the day 5 times",
+		rule:        func(p *Progress) bool { return p.FirstSolves >= 5 },
+	},
+	{
+		ID:          "all_categories",
+		Emoji:       "🧭",
+		Name:        "Explorer",
+		Description: "Solve a daily challenge in every category posted so far",
+		rule: func(p *Progress) bool {
+			return p.TotalCategories > 0 && p.CategoriesSolved >= p.TotalCategories
+		},
+	},
+	{
+		ID:          "fast_hard",
+		Emoji:       "🚀",
+		Name:        "Speed Demon",
+		Description: "Solve a Hard problem within an hour of the post",
+		rule:        func(p *Progress) bool { return p.FastHardSolves >= 1 },
+	},
+}
+
+// Earned returns the achievements whose rules hold for the given progress
+func Earned(p *Progress) []Achievement {
+	var earned []Achievement
+	for _, a := range All {
+		if a.rule(p) {
+			earned = append(earned, a)
+		}
+	}
+	return earned
+}
+
+// ByID looks up an achievement by its ID
+func ByID(id string) (Achievement, bool) {
+	for _, a := range All {
+		if a.ID == id {
+			return a, true
+		}
+	}
+	return Achievement{}, false
+}
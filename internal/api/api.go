@@ -0,0 +1,351 @@
+package api
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"leetcode-telegram-bot/internal/bot"
+	"leetcode-telegram-bot/internal/config"
+	"leetcode-telegram-bot/internal/database"
+	"leetcode-telegram-bot/internal/judge"
+	"leetcode-telegram-bot/internal/models"
+	"leetcode-telegram-bot/internal/server"
+)
+
+// basePath is where version 1 of the API is served
+const basePath = "/api/v1/"
+
+// maxBodySize limits the size of request bodies
+const maxBodySize = 1 << 20
+
+// windows are the time windows the leaderboard can be requested for, in days. Zero is all time.
+var windows = map[string]int{
+	"week":  7,
+	"month": 30,
+	"all":   0,
+}
+
+// API is a JSON API over the bot's data for other tools. Reads need the API token or the
+// admin token, writes need the admin token.
+type API struct {
+	db     *database.DB
+	bot    *bot.Bot
+	config *config.Config
+}
+
+// New creates the API
+func New(db *database.DB, b *bot.Bot, cfg *config.Config) *API {
+	return &API{db: db, bot: b, config: cfg}
+}
+
+// RegisterHandlers adds the API endpoints to the server
+func (a *API) RegisterHandlers(s *server.Server) {
+	s.Handle(basePath, http.HandlerFunc(a.route))
+}
+
+// TodaysChallenge is today's challenge with the members who solved it so far
+type TodaysChallenge struct {
+	DayNumber int                 `json:"day_number"`
+	Date      string              `json:"date"`
+	Problem   *models.Problem     `json:"problem"`
+	Solves    []models.SolveEntry `json:"solves"`
+}
+
+// route checks the bearer token of a request and dispatches it on its method and path.
+// Admin endpoints only accept the admin token.
+func (a *API) route(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, basePath), "/")
+	segments := strings.Split(path, "/")
+	admin := segments[0] == "admin"
+
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	switch {
+	case tokenMatches(token, a.config.APIAdminToken):
+	case tokenMatches(token, a.config.APIToken):
+		if admin {
+			writeError(w, http.StatusForbidden, "this endpoint needs the admin token")
+			return
+		}
+	default:
+		w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+		writeError(w, http.StatusUnauthorized, "missing or invalid token")
+		return
+	}
+
+	switch {
+	case path == "challenges":
+		a.get(w, r, a.handleChallenges)
+	case path == "challenges/today":
+		a.get(w, r, a.handleTodaysChallenge)
+	case path == "leaderboard":
+		a.get(w, r, a.handleLeaderboard)
+	case len(segments) == 3 && segments[0] == "users" && segments[2] == "stats":
+		a.get(w, r, func(w http.ResponseWriter, r *http.Request) { a.handleUserStats(w, r, segments[1]) })
+	case path == "admin/post":
+		a.post(w, r, a.handlePost)
+	case path == "admin/reminder":
+		a.post(w, r, a.handleReminder)
+	case path == "admin/problems":
+		a.post(w, r, a.handleAddProblem)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// tokenMatches compares a token in constant time. An unset token never matches.
+func tokenMatches(token, expected string) bool {
+	return expected != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+func (a *API) get(w http.ResponseWriter, r *http.Request, handler http.HandlerFunc) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	handler(w, r)
+}
+
+func (a *API) post(w http.ResponseWriter, r *http.Request, handler http.HandlerFunc) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	handler(w, r)
+}
+
+// handleChallenges lists past daily challenges, optionally between ?from= and ?to= (YYYY-MM-DD)
+func (a *API) handleChallenges(w http.ResponseWriter, r *http.Request) {
+	from := r.URL.Query().Get("from")
+	if from == "" {
+		from = "0000-01-01"
+	}
+	to := r.URL.Query().Get("to")
+	if to == "" {
+		to = a.bot.Now().Format("2006-01-02")
+	}
+	for _, date := range []string{from, to} {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			writeError(w, http.StatusBadRequest, "dates must be formatted as YYYY-MM-DD")
+			return
+		}
+	}
+
+	history, err := a.db.GetChallengeHistory(from, to)
+	if err != nil {
+		serverError(w, "challenge history", err)
+		return
+	}
+	if history == nil {
+		history = []models.ChallengeHistoryEntry{}
+	}
+	writeJSON(w, http.StatusOK, history)
+}
+
+// handleTodaysChallenge returns today's challenge and who solved it
+func (a *API) handleTodaysChallenge(w http.ResponseWriter, r *http.Request) {
+	today := a.bot.Now().Format("2006-01-02")
+	challenge, err := a.todaysChallenge(today)
+	if err == sql.ErrNoRows {
+		writeError(w, http.StatusNotFound, "no challenge has been posted today")
+		return
+	}
+	if err != nil {
+		serverError(w, "today's challenge", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, challenge)
+}
+
+func (a *API) todaysChallenge(today string) (*TodaysChallenge, error) {
+	problem, dayNumber, err := a.db.GetTodaysChallengeWithDay(today)
+	if err != nil {
+		return nil, err
+	}
+	solves, err := a.db.GetSolves(today)
+	if err != nil {
+		return nil, err
+	}
+	if solves == nil {
+		solves = []models.SolveEntry{}
+	}
+	return &TodaysChallenge{DayNumber: dayNumber, Date: today, Problem: problem, Solves: solves}, nil
+}
+
+// handleLeaderboard returns the leaderboard for ?window=week|month|all, all time by default
+func (a *API) handleLeaderboard(w http.ResponseWriter, r *http.Request) {
+	window := r.URL.Query().Get("window")
+	if window == "" {
+		window = "all"
+	}
+	days, ok := windows[window]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "window must be week, month or all")
+		return
+	}
+
+	limit := 100
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive number")
+			return
+		}
+		limit = parsed
+	}
+
+	var leaderboard []models.LeaderboardEntry
+	var err error
+	if days == 0 {
+		leaderboard, err = a.db.GetLeaderboard(limit, a.config.HintPenalty)
+	} else {
+		today := a.bot.Now()
+		from := today.AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+		leaderboard, err = a.db.GetLeaderboardBetween(from, today.Format("2006-01-02"), limit, a.config.HintPenalty)
+	}
+	if err != nil {
+		serverError(w, "leaderboard", err)
+		return
+	}
+	if leaderboard == nil {
+		leaderboard = []models.LeaderboardEntry{}
+	}
+	writeJSON(w, http.StatusOK, leaderboard)
+}
+
+// handleUserStats returns the stats of one member
+func (a *API) handleUserStats(w http.ResponseWriter, r *http.Request, id string) {
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	stats, err := a.db.GetUserStats(userID, a.bot.Now().Format("2006-01-02"))
+	if err == sql.ErrNoRows {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		serverError(w, "user stats", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// handlePost posts today's challenge to the group, if it hasn't been posted yet
+func (a *API) handlePost(w http.ResponseWriter, r *http.Request) {
+	today := a.bot.Now().Format("2006-01-02")
+	if _, err := a.db.GetDailyChallenge(today); err == nil {
+		writeError(w, http.StatusConflict, "today's challenge has already been posted")
+		return
+	} else if err != sql.ErrNoRows {
+		serverError(w, "daily challenge", err)
+		return
+	}
+
+	if err := a.bot.PostDailyChallenge(); errors.Is(err, bot.ErrDaySkipped) {
+		writeError(w, http.StatusConflict, "today is skipped, nothing was posted")
+		return
+	} else if err != nil {
+		serverError(w, "daily challenge post", err)
+		return
+	}
+
+	challenge, err := a.todaysChallenge(today)
+	if err != nil {
+		serverError(w, "today's challenge", err)
+		return
+	}
+	log.Printf("Daily challenge posted through the API")
+	writeJSON(w, http.StatusCreated, challenge)
+}
+
+// handleReminder reminds members who haven't solved today's challenge yet
+func (a *API) handleReminder(w http.ResponseWriter, r *http.Request) {
+	if err := a.bot.SendReminder(); err != nil {
+		serverError(w, "reminder", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "sent"})
+}
+
+// handleAddProblem adds a problem to the pool from a JSON body with title, url, category
+// and optionally platform
+func (a *API) handleAddProblem(w http.ResponseWriter, r *http.Request) {
+	var problem models.Problem
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&problem); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	problem.Title = strings.TrimSpace(problem.Title)
+	problem.URL = strings.TrimSpace(problem.URL)
+	problem.Category = strings.TrimSpace(problem.Category)
+	if problem.Title == "" || problem.URL == "" || problem.Category == "" {
+		writeError(w, http.StatusBadRequest, "title, url and category are required")
+		return
+	}
+	if !strings.HasPrefix(problem.URL, "https://") && !strings.HasPrefix(problem.URL, "http://") {
+		writeError(w, http.StatusBadRequest, "url must be an http(s) link")
+		return
+	}
+	if problem.Platform != "" {
+		if _, err := judge.ParsePlatform(problem.Platform); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if existing, err := a.db.GetProblemByTitle(problem.Title); err == nil {
+		writeJSON(w, http.StatusConflict, existing)
+		return
+	} else if err != sql.ErrNoRows {
+		serverError(w, "problem", err)
+		return
+	}
+
+	if err := a.db.AddProblem(&problem); err != nil {
+		serverError(w, "new problem", err)
+		return
+	}
+	added, err := a.db.GetProblemByTitle(problem.Title)
+	if err != nil {
+		serverError(w, "new problem", err)
+		return
+	}
+	log.Printf("Problem %q added through the API", added.Title)
+	writeJSON(w, http.StatusCreated, added)
+}
+
+// writeJSON writes v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		serverError(w, "response", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(body, '\n'))
+}
+
+// writeError writes an error response as {"error": message}
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+func serverError(w http.ResponseWriter, what string, err error) {
+	log.Printf("Error serving API %s: %v", what, err)
+	writeError(w, http.StatusInternalServerError, "something went wrong, please try again later")
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"leetcode-telegram-bot/internal/config"
+)
+
+func TestRouteTokens(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiToken   string
+		adminToken string
+		header     string
+		method     string
+		path       string
+		want       int
+	}{
+		{"no token", "read", "admin", "", http.MethodGet, "unknown", http.StatusUnauthorized},
+		{"wrong token", "read", "admin", "Bearer nope", http.MethodGet, "unknown", http.StatusUnauthorized},
+		{"read token", "read", "admin", "Bearer read", http.MethodGet, "unknown", http.StatusNotFound},
+		{"read token on admin endpoint", "read", "admin", "Bearer read", http.MethodPost, "admin/post", http.StatusForbidden},
+		{"admin token", "read", "admin", "Bearer admin", http.MethodGet, "unknown", http.StatusNotFound},
+		{"admin token on admin endpoint", "read", "admin", "Bearer admin", http.MethodGet, "admin/post", http.StatusMethodNotAllowed},
+		{"empty token with no read token", "", "admin", "Bearer ", http.MethodGet, "unknown", http.StatusUnauthorized},
+		{"empty token with no tokens", "", "", "Bearer ", http.MethodGet, "unknown", http.StatusUnauthorized},
+		{"no token with no tokens", "", "", "", http.MethodGet, "unknown", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		a := New(nil, nil, &config.Config{APIToken: tt.apiToken, APIAdminToken: tt.adminToken})
+		req := httptest.NewRequest(tt.method, basePath+tt.path, nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		rec := httptest.NewRecorder()
+		a.route(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
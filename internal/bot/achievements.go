@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"leetcode-telegram-bot/internal/achievements"
+	"leetcode-telegram-bot/internal/chat"
+	"leetcode-telegram-bot/internal/messages"
+)
+
+// fastSolveSeconds is how soon after the post a Hard problem counts as solved fast
+const fastSolveSeconds = 60 * 60
+
+// checkAchievements unlocks the achievements a user has earned with their submissions
+// up to the given date and announces the new ones in every group
+func (b *Bot) checkAchievements(userID int64, date string) error {
+	stats, err := b.db.GetUserStats(userID, date)
+	if err != nil {
+		return fmt.Errorf("failed to get user stats: %w", err)
+	}
+	firstSolves, err := b.db.CountFirstSolves(userID)
+	if err != nil {
+		return fmt.Errorf("failed to count first solves: %w", err)
+	}
+	fastHardSolves, err := b.db.CountFastSolves(userID, "Hard", fastSolveSeconds)
+	if err != nil {
+		return fmt.Errorf("failed to count fast solves: %w", err)
+	}
+	totalCategories, err := b.db.CountCategories()
+	if err != nil {
+		return fmt.Errorf("failed to count categories: %w", err)
+	}
+
+	progress := &achievements.Progress{
+		TotalSolved:      stats.TotalSolved,
+		CurrentStreak:    stats.CurrentStreak,
+		LongestStreak:    stats.LongestStreak,
+		FirstSolves:      firstSolves,
+		CategoriesSolved: stats.CategoriesSolved,
+		TotalCategories:  totalCategories,
+		FastHardSolves:   fastHardSolves,
+	}
+
+	var unlocked []achievements.Achievement
+	for _, achievement := range achievements.Earned(progress) {
+		isNew, err := b.db.UnlockAchievement(userID, achievement.ID)
+		if err != nil {
+			log.Printf("Error unlocking achievement %s for user %d: %v", achievement.ID, userID, err)
+			continue
+		}
+		if isNew {
+			unlocked = append(unlocked, achievement)
+		}
+	}
+
+	if len(unlocked) == 0 {
+		return nil
+	}
+
+	data := messages.AchievementsData{Name: chat.Escape(stats.User.FirstName), Achievements: unlocked}
+	return b.sendToGroups(b.groups, func(platform string) string {
+		return b.messages.Render(b.language(platform), messages.Achievements, data)
+	})
+}
+
+// formatAchievements lists unlocked achievements by their emoji and name
+func formatAchievements(ids []string) string {
+	titles := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if achievement, ok := achievements.ByID(id); ok {
+			titles = append(titles, achievement.Title())
+		}
+	}
+	return strings.Join(titles, ", ")
+}
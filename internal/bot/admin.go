@@ -0,0 +1,200 @@
+package bot
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"leetcode-telegram-bot/internal/chat"
+	"leetcode-telegram-bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// isMainGroupMessage reports whether a message was sent from the main group,
+// replying with an error when it was not
+func (b *Bot) isMainGroupMessage(message *tgbotapi.Message) bool {
+	if message.From.ID != b.config.TelegramGroupID && message.Chat.ID != b.config.TelegramGroupID {
+		b.sendMessage(message.Chat.ID, "❌ This command can only be used in the main group.")
+		return false
+	}
+	return true
+}
+
+// isGroupAdmin reports whether the sender of a message is an administrator of the main group.
+// Anonymous admins send their messages on behalf of the group itself.
+func (b *Bot) isGroupAdmin(message *tgbotapi.Message) bool {
+	if message.SenderChat != nil && message.SenderChat.ID == b.config.TelegramGroupID {
+		return true
+	}
+
+	member, err := b.api.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: b.config.TelegramGroupID, UserID: message.From.ID},
+	})
+	if err != nil {
+		log.Printf("Error getting chat member %d: %v", message.From.ID, err)
+		return false
+	}
+	return member.IsCreator() || member.IsAdministrator()
+}
+
+// isGroupAdminMessage reports whether a message was sent from the main group by one of its
+// administrators, replying with an error when it was not
+func (b *Bot) isGroupAdminMessage(message *tgbotapi.Message) bool {
+	if !b.isMainGroupMessage(message) {
+		return false
+	}
+	if !b.isGroupAdmin(message) {
+		b.sendMessage(message.Chat.ID, "❌ Only admins of the main group can use this command.")
+		return false
+	}
+	return true
+}
+
+// handleRerollCommand handles the /reroll command for replacing today's problem
+func (b *Bot) handleRerollCommand(message *tgbotapi.Message) {
+	if !b.isGroupAdminMessage(message) {
+		return
+	}
+
+	problem, err := b.nextProblem(b.today())
+	if err != nil {
+		log.Printf("Error getting next problem: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ No unused problems left to re-roll with.")
+		return
+	}
+
+	if err := b.ReplaceDailyChallenge(problem); err != nil {
+		log.Printf("Error in reroll command: %v", err)
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error re-rolling today's challenge: %v", err))
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("🎲 Today's challenge has been re-rolled: **%s**", chat.Escape(problem.Title)))
+}
+
+// handleSkipCommand handles the /skip command for cancelling today's challenge
+func (b *Bot) handleSkipCommand(message *tgbotapi.Message) {
+	if !b.isGroupAdminMessage(message) {
+		return
+	}
+
+	if err := b.SkipDailyChallenge(); err != nil {
+		log.Printf("Error in skip command: %v", err)
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error skipping today's challenge: %v", err))
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, "⏭️ Today's challenge has been cancelled. Streaks are not affected.")
+}
+
+// handlePickCommand handles the /pick command for posting a specific problem
+func (b *Bot) handlePickCommand(message *tgbotapi.Message) {
+	if !b.isGroupAdminMessage(message) {
+		return
+	}
+
+	slug := strings.Trim(strings.TrimSpace(message.CommandArguments()), "/")
+	if slug == "" {
+		b.sendMessage(message.Chat.ID, "❌ Please provide a problem slug. Usage: /pick <slug> (e.g. /pick two-sum)")
+		return
+	}
+
+	problem, err := b.db.GetProblemBySlug(slug)
+	if err == sql.ErrNoRows {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Problem %s was not found in the problem list.", chat.Escape(slug)))
+		return
+	}
+	if err != nil {
+		log.Printf("Error getting problem %s: %v", slug, err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while looking up the problem.")
+		return
+	}
+
+	today := b.today()
+	if _, lookupErr := b.db.GetDailyChallenge(today); lookupErr == nil {
+		err = b.ReplaceDailyChallenge(problem)
+	} else {
+		err = b.postDailyChallenge(problem)
+	}
+	if err != nil {
+		log.Printf("Error in pick command: %v", err)
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error posting %s: %v", chat.Escape(problem.Title), err))
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Today's challenge is now **%s**", chat.Escape(problem.Title)))
+}
+
+// ReplaceDailyChallenge swaps today's problem for another one, keeping the day number
+// and editing the original announcement in place
+func (b *Bot) ReplaceDailyChallenge(problem *models.Problem) error {
+	today := b.today()
+
+	challenge, err := b.db.GetDailyChallenge(today)
+	if err != nil {
+		return fmt.Errorf("no challenge has been posted today")
+	}
+	if challenge.ProblemID == problem.ID {
+		return fmt.Errorf("%s is already today's challenge", problem.Title)
+	}
+
+	if err := b.db.ReplaceDailyChallengeProblem(today, problem.ID); err != nil {
+		return fmt.Errorf("failed to replace daily challenge: %w", err)
+	}
+	if err := b.db.MarkTrackProblemPosted(problem.ID, today); err != nil {
+		log.Printf("Error updating track progress: %v", err)
+	}
+
+	if err := b.refreshDailyAnnouncement(today); err != nil {
+		log.Printf("Error updating daily announcement: %v", err)
+	}
+	if err := b.refreshProgressBoard(today); err != nil {
+		log.Printf("Error updating progress board: %v", err)
+	}
+
+	log.Printf("Replaced daily challenge Day %d with %s", challenge.DayNumber, problem.Title)
+	return nil
+}
+
+// SkipDailyChallenge cancels today's challenge without counting it against anyone's streak
+func (b *Bot) SkipDailyChallenge() error {
+	today := b.today()
+
+	challenge, err := b.db.SkipDay(today)
+	if err != nil {
+		return fmt.Errorf("failed to skip today's challenge: %w", err)
+	}
+
+	if challenge == nil {
+		log.Printf("Skipped challenge for %s", today)
+		return nil
+	}
+
+	text := fmt.Sprintf("⏭️ **Day %d has been cancelled**\n\nNo challenge today, enjoy the break! 🎉", challenge.DayNumber)
+	if challenge.MessageID != 0 {
+		if err := b.editMessage(b.config.TelegramGroupID, challenge.MessageID, text, nil); err != nil {
+			log.Printf("Error editing cancelled announcement: %v", err)
+		}
+	}
+	b.editGroupMessages(today, announcementKind, text)
+
+	board := fmt.Sprintf("📋 **Day %d Progress** 📋\n\nCancelled, no challenge today.", challenge.DayNumber)
+	b.editGroupMessages(today, boardKind, board)
+	if challenge.BoardMessageID != 0 && !challenge.BoardFinalized {
+		if err := b.editMessage(b.config.TelegramGroupID, challenge.BoardMessageID, board, nil); err != nil {
+			log.Printf("Error editing cancelled progress board: %v", err)
+		}
+		unpin := tgbotapi.UnpinChatMessageConfig{
+			ChatID:    b.config.TelegramGroupID,
+			MessageID: challenge.BoardMessageID,
+		}
+		if _, err := b.api.Request(unpin); err != nil {
+			log.Printf("Error unpinning cancelled progress board: %v", err)
+		}
+	}
+
+	log.Printf("Skipped challenge for %s", today)
+	return nil
+}
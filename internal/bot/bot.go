@@ -2,24 +2,56 @@ package bot
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 	"time"
 
+	"leetcode-telegram-bot/internal/chat"
+	"leetcode-telegram-bot/internal/codeforces"
 	"leetcode-telegram-bot/internal/config"
 	"leetcode-telegram-bot/internal/database"
+	"leetcode-telegram-bot/internal/events"
+	"leetcode-telegram-bot/internal/judge"
 	"leetcode-telegram-bot/internal/leetcode"
+	"leetcode-telegram-bot/internal/messages"
+	"leetcode-telegram-bot/internal/metrics"
 	"leetcode-telegram-bot/internal/models"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
+// ErrDaySkipped is returned by PostDailyChallenge when today's challenge was cancelled with /skip
+var ErrDaySkipped = errors.New("today's challenge was skipped")
+
+// speedLeaderboardMinSolves is how many timed solves a user needs to appear on the speed leaderboard
+const speedLeaderboardMinSolves = 3
+
 // Bot represents the Telegram bot
 type Bot struct {
-	api    *tgbotapi.BotAPI
-	db     *database.DB
-	config *config.Config
+	api      *tgbotapi.BotAPI
+	db       *database.DB
+	config   *config.Config
+	location *time.Location
+
+	// Checkers of the judges whose solves can be verified
+	judges map[judge.Platform]judge.Checker
+
+	// Queue of events for the outbound webhooks
+	events *events.Dispatcher
+
+	// Chat platform adapters. Slack and Discord are nil unless configured.
+	telegram *chat.Telegram
+	slack    *chat.Slack
+	discord  *chat.Discord
+	// Chats that run the challenge program, the main Telegram group first
+	groups []chat.Group
+
+	// Message templates in every language
+	messages *messages.Catalog
 }
 
 // New creates a new Telegram bot instance
@@ -32,15 +64,65 @@ func New(token string, db *database.DB, cfg *config.Config) (*Bot, error) {
 	api.Debug = false
 	log.Printf("Authorized on account %s", api.Self.UserName)
 
-	return &Bot{
-		api:    api,
-		db:     db,
-		config: cfg,
-	}, nil
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		log.Printf("Failed to load timezone %s, using UTC: %v", cfg.Timezone, err)
+		loc = time.UTC
+	}
+
+	catalog, err := messages.Load(cfg.TemplatesDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load message templates: %w", err)
+	}
+	if !catalog.HasLanguage(cfg.DefaultLanguage) {
+		return nil, fmt.Errorf("invalid DEFAULT_LANGUAGE %q, expected one of %s", cfg.DefaultLanguage, strings.Join(catalog.Languages(), ", "))
+	}
+
+	b := &Bot{
+		api:      api,
+		db:       db,
+		config:   cfg,
+		location: loc,
+		judges: map[judge.Platform]judge.Checker{
+			judge.LeetCode:   leetcode.Checker{},
+			judge.Codeforces: codeforces.Checker{},
+		},
+		events:   events.New(db),
+		telegram: chat.NewTelegram(api),
+		messages: catalog,
+	}
+	b.groups = []chat.Group{{Platform: b.telegram, ChatID: strconv.FormatInt(cfg.TelegramGroupID, 10)}}
+
+	if cfg.SlackBotToken != "" {
+		b.slack = chat.NewSlack(cfg.SlackBotToken, cfg.SlackSigningSecret)
+		b.groups = append(b.groups, chat.Group{Platform: b.slack, ChatID: cfg.SlackChannelID})
+	}
+	if cfg.DiscordBotToken != "" {
+		b.discord, err = chat.NewDiscord(cfg.DiscordBotToken, cfg.DiscordApplicationID, cfg.DiscordPublicKey)
+		if err != nil {
+			return nil, err
+		}
+		if err := b.discord.RegisterCommand(); err != nil {
+			log.Printf("Error registering the Discord slash command: %v", err)
+		}
+		b.groups = append(b.groups, chat.Group{Platform: b.discord, ChatID: cfg.DiscordChannelID})
+	}
+
+	return b, nil
 }
 
-// Start starts the bot and handles incoming messages
-func (b *Bot) Start(ctx context.Context) {
+// Start starts the bot and handles incoming messages, through the webhook when WEBHOOK_URL
+// is set and with long polling otherwise. It returns once ctx is cancelled.
+func (b *Bot) Start(ctx context.Context) error {
+	if b.config.WebhookURL != "" {
+		return b.serveWebhook(ctx)
+	}
+
+	// getUpdates fails while a webhook is set, e.g. after switching back from webhook mode
+	if _, err := b.api.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 
@@ -50,15 +132,31 @@ func (b *Bot) Start(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			log.Println("Bot stopping...")
-			return
+			b.api.StopReceivingUpdates()
+			return nil
 		case update := <-updates:
-			if update.Message != nil {
-				go b.handleMessage(update.Message)
-			}
+			b.dispatch(update)
 		}
 	}
 }
 
+// Ready checks that the bot token is still accepted by Telegram
+func (b *Bot) Ready() error {
+	if _, err := b.api.GetMe(); err != nil {
+		return fmt.Errorf("telegram authorization failed: %w", err)
+	}
+	return nil
+}
+
+// dispatch hands an update to its handler, the same way for polling and the webhook
+func (b *Bot) dispatch(update tgbotapi.Update) {
+	if update.Message != nil {
+		go b.handleMessage(update.Message)
+	} else if update.CallbackQuery != nil {
+		go b.handleCallbackQuery(update.CallbackQuery)
+	}
+}
+
 // handleMessage processes incoming messages
 func (b *Bot) handleMessage(message *tgbotapi.Message) {
 	// Save user info
@@ -74,7 +172,8 @@ func (b *Bot) handleMessage(message *tgbotapi.Message) {
 
 	// Handle commands
 	if message.IsCommand() {
-		switch message.Command() {
+		command := message.Command()
+		switch command {
 		// case "submit":
 		// 	b.handleSubmitCommand(message)
 		case "leaderboards":
@@ -91,15 +190,61 @@ func (b *Bot) handleMessage(message *tgbotapi.Message) {
 			b.handleResetDayCommand(message)
 		case "register":
 			b.handleRegisterLeetcodeProfile(message)
+		case "unregister":
+			b.handleUnregisterCommand(message)
+		case "verify":
+			b.handleVerifyCommand(message)
+		case "link":
+			b.handleLinkCommand(message)
+		case "reroll":
+			b.handleRerollCommand(message)
+		case "skip":
+			b.handleSkipCommand(message)
+		case "pick":
+			b.handlePickCommand(message)
+		case "queue":
+			b.handleQueueCommand(message)
+		case "track":
+			b.handleTrackCommand(message)
+		case "practice":
+			b.handlePracticeCommand(message)
+		case "digest":
+			b.handleDigestCommand(message)
+		case "stats":
+			b.handleStatsCommand(message)
+		case "profile":
+			b.handleProfileCommand(message)
+		case "hint":
+			b.handleHintCommand(message)
+		case "addhint":
+			b.handleAddHintCommand(message)
+		case "solution":
+			b.handleSolutionCommand(message)
+		case "solutions":
+			b.handleSolutionsCommand(message)
+		case "webhook":
+			b.handleWebhookCommand(message)
+		case "language":
+			b.handleLanguageCommand(message)
+		case "templates":
+			b.handleTemplatesCommand(message)
 		default:
+			command = "unknown"
 			b.sendMessage(message.Chat.ID, "Unknown command. Use /help to see available commands.")
 		}
+		metrics.Commands.Inc(command)
+		return
+	}
+
+	// Handle replies to the bot, e.g. solutions sent after /solution <language>
+	if message.ReplyToMessage != nil && message.ReplyToMessage.From != nil && message.ReplyToMessage.From.ID == b.api.Self.ID {
+		b.handleReplyToBot(message)
 	}
 }
 
 // handleSubmitCommand handles the /submit command
 func (b *Bot) handleSubmitCommand(message *tgbotapi.Message) {
-	today := time.Now().Format("2006-01-02")
+	today := b.today()
 
 	// Check if user already submitted today
 	hasSubmitted, err := b.db.HasUserSubmittedToday(message.From.ID, today)
@@ -129,7 +274,7 @@ func (b *Bot) handleSubmitCommand(message *tgbotapi.Message) {
 		Date:      today,
 	}
 
-	if err := b.db.AddSubmission(submission); err != nil {
+	if _, err := b.recordSubmission(submission); err != nil {
 		log.Printf("Error adding submission: %v", err)
 		b.sendMessage(message.Chat.ID, "❌ An error occurred while submitting.")
 		return
@@ -138,81 +283,134 @@ func (b *Bot) handleSubmitCommand(message *tgbotapi.Message) {
 	responseText := fmt.Sprintf("🎉 Great job! You've successfully submitted Day %d challenge:\n\n"+
 		"📝 **%s**\n"+
 		"🔗 %s\n\n"+
-		"Keep up the good work! 💪", dayNumber, todaysChallenge.Title, todaysChallenge.URL)
+		"Keep up the good work! 💪", dayNumber, chat.Escape(todaysChallenge.Title), todaysChallenge.URL)
 
 	b.sendMessage(message.Chat.ID, responseText)
 }
 
 // handleLeaderboardCommand handles the /leaderboards command
 func (b *Bot) handleLeaderboardCommand(message *tgbotapi.Message) {
-	leaderboard, err := b.db.GetLeaderboard(10)
+	if strings.EqualFold(strings.TrimSpace(message.CommandArguments()), "speed") {
+		b.handleSpeedLeaderboard(message)
+		return
+	}
+
+	text, err := b.leaderboardText(b.language(chat.PlatformTelegram))
 	if err != nil {
 		log.Printf("Error getting leaderboard: %v", err)
 		b.sendMessage(message.Chat.ID, "❌ An error occurred while fetching the leaderboard.")
 		return
 	}
 
+	b.sendMessage(message.Chat.ID, text)
+}
+
+// leaderboardText formats the top 10 of the overall leaderboard in the given language
+func (b *Bot) leaderboardText(language string) (string, error) {
+	leaderboard, err := b.db.GetLeaderboard(10, b.config.HintPenalty)
+	if err != nil {
+		return "", err
+	}
+
+	data := messages.LeaderboardData{ShowPoints: b.config.HintPenalty > 0}
+	for i, entry := range leaderboard {
+		data.Entries = append(data.Entries, messages.LeaderboardEntry{
+			Rank:   rankEmoji(i),
+			Name:   displayName(entry.FirstName, entry.LastName, entry.Username),
+			Solved: entry.TotalSolved,
+			Points: entry.Points,
+		})
+	}
+
+	return b.messages.Render(language, messages.Leaderboard, data), nil
+}
+
+// handleSpeedLeaderboard handles /leaderboards speed, ranking users by their average
+// time from the daily post to an accepted submission on LeetCode
+func (b *Bot) handleSpeedLeaderboard(message *tgbotapi.Message) {
+	leaderboard, err := b.db.GetSpeedLeaderboard(speedLeaderboardMinSolves, 10)
+	if err != nil {
+		log.Printf("Error getting speed leaderboard: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while fetching the leaderboard.")
+		return
+	}
+
 	if len(leaderboard) == 0 {
-		b.sendMessage(message.Chat.ID, "📊 No submissions yet! Be the first to submit a challenge.")
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("⚡ Nobody has %d verified solves with a known time yet.", speedLeaderboardMinSolves))
 		return
 	}
 
 	var responseText strings.Builder
-	responseText.WriteString("🏆 **LeetCode Challenge Leaderboard** 🏆\n\n")
+	responseText.WriteString("⚡ **Speed Leaderboard** ⚡\n")
+	responseText.WriteString(fmt.Sprintf("Average time from post to accepted, at least %d verified solves\n\n", speedLeaderboardMinSolves))
 
 	for i, entry := range leaderboard {
-		var emoji string
-		switch i {
-		case 0:
-			emoji = "🥇"
-		case 1:
-			emoji = "🥈"
-		case 2:
-			emoji = "🥉"
-		default:
-			emoji = fmt.Sprintf("%d.", i+1)
-		}
+		responseText.WriteString(fmt.Sprintf("%s %s - %s average, best %s (%d solves)\n",
+			rankEmoji(i),
+			displayName(entry.FirstName, entry.LastName, entry.Username),
+			formatSolveTime(time.Duration(entry.AverageSeconds)*time.Second),
+			formatSolveTime(time.Duration(entry.BestSeconds)*time.Second),
+			entry.Solves))
+	}
 
-		name := entry.FirstName
-		if entry.LastName != "" {
-			name += " " + entry.LastName
-		}
-		if entry.Username != "" {
-			name += fmt.Sprintf(" (@%s)", entry.Username)
-		}
+	b.sendMessage(message.Chat.ID, responseText.String())
+}
 
-		responseText.WriteString(fmt.Sprintf("%s %s - %d solved\n", emoji, name, entry.TotalSolved))
+// rankEmoji returns the medal for the top three places and the rank number for the rest
+func rankEmoji(i int) string {
+	switch i {
+	case 0:
+		return "🥇"
+	case 1:
+		return "🥈"
+	case 2:
+		return "🥉"
+	default:
+		return fmt.Sprintf("%d.", i+1)
 	}
+}
 
-	responseText.WriteString("\n💪 Keep solving to climb the ranks!")
-
-	b.sendMessage(message.Chat.ID, responseText.String())
+// now returns the current time in TIMEZONE, the timezone the cron jobs run in
+func (b *Bot) now() time.Time {
+	return time.Now().In(b.location)
 }
 
-// handleHelpCommand handles the /help command
-func (b *Bot) handleHelpCommand(message *tgbotapi.Message) {
-	helpText := `🤖 **LeetCode Challenge Bot Help**
+// today returns the current date in TIMEZONE. Challenges, submissions and every other
+// record of a day are keyed by it.
+func (b *Bot) today() string {
+	return b.now().Format("2006-01-02")
+}
 
-Available commands:
-• /submit - Submit today's challenge
-• /leaderboards - View the leaderboard
-• /status - Show bot status and current day info
-• /help - Show this help message
+// Now returns the current time in TIMEZONE, for other packages that look up a day's records
+func (b *Bot) Now() time.Time {
+	return b.now()
+}
 
-**Admin Commands (Group only):**
-• /manual - Manually post daily challenge immediately
-• /testreminder - Test reminder functionality
-• /resetday - Reset day counter (next challenge will be Day 9)
+// escapeProblem returns a copy of a problem with its title and category escaped for a message
+func escapeProblem(problem *models.Problem) *models.Problem {
+	escaped := *problem
+	escaped.Title = chat.Escape(problem.Title)
+	escaped.Category = chat.Escape(problem.Category)
+	return &escaped
+}
 
-📅 **How it works:**
-- Every weekday (Monday to Friday) at 7:00 AM, I post a new LeetCode challenge
-- Challenge numbering starts from Day 9
-- Use /submit to mark that you've completed it
-- Check /leaderboards to see who's solving the most problems
-- I'll remind you at 3:00 PM and 10:00 PM if you haven't submitted yet
-- No challenges on weekends (Saturday & Sunday) 🎉
+// displayName formats a user's full name followed by their @username if they have one,
+// escaped for a message
+func displayName(firstName, lastName, username string) string {
+	name := firstName
+	if lastName != "" {
+		name += " " + lastName
+	}
+	if username != "" {
+		name += fmt.Sprintf(" (@%s)", username)
+	}
+	return chat.Escape(name)
+}
 
-Happy coding! 💻✨`
+// handleHelpCommand handles the /help command
+func (b *Bot) handleHelpCommand(message *tgbotapi.Message) {
+	helpText := b.messages.Render(b.language(chat.PlatformTelegram), messages.Help,
+		messages.HelpData{SolutionRevealTime: b.config.SolutionRevealTime})
 
 	b.sendMessage(message.Chat.ID, helpText)
 }
@@ -220,14 +418,15 @@ Happy coding! 💻✨`
 // handleManualCommand handles the /manual command for manually posting daily challenge
 func (b *Bot) handleManualCommand(message *tgbotapi.Message) {
 	// Check if user is admin (you can customize this logic)
-	if message.From.ID != b.config.TelegramGroupID && message.Chat.ID != b.config.TelegramGroupID {
-		b.sendMessage(message.Chat.ID, "❌ This command can only be used in the main group.")
+	if !b.isMainGroupMessage(message) {
 		return
 	}
 
 	b.sendMessage(message.Chat.ID, "📝 Manually posting daily challenge...")
 
-	if err := b.PostDailyChallenge(); err != nil {
+	if err := b.PostDailyChallenge(); errors.Is(err, ErrDaySkipped) {
+		b.sendMessage(message.Chat.ID, "⏭️ Today's challenge was cancelled with /skip, nothing was posted.")
+	} else if err != nil {
 		log.Printf("Error in manual command: %v", err)
 		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error posting manual challenge: %v", err))
 	} else {
@@ -238,8 +437,7 @@ func (b *Bot) handleManualCommand(message *tgbotapi.Message) {
 // handleTestReminderCommand handles the /testreminder command for testing reminders
 func (b *Bot) handleTestReminderCommand(message *tgbotapi.Message) {
 	// Check if user is admin (you can customize this logic)
-	if message.From.ID != b.config.TelegramGroupID && message.Chat.ID != b.config.TelegramGroupID {
-		b.sendMessage(message.Chat.ID, "❌ This command can only be used in the main group.")
+	if !b.isMainGroupMessage(message) {
 		return
 	}
 
@@ -255,7 +453,7 @@ func (b *Bot) handleTestReminderCommand(message *tgbotapi.Message) {
 
 // handleStatusCommand handles the /status command for showing bot status
 func (b *Bot) handleStatusCommand(message *tgbotapi.Message) {
-	today := time.Now().Format("2006-01-02")
+	today := b.today()
 
 	// Get current day number
 	currentDay, err := b.db.GetCurrentDayNumber()
@@ -270,16 +468,22 @@ func (b *Bot) handleStatusCommand(message *tgbotapi.Message) {
 	if err != nil {
 		challengeStatus = "❌ No challenge posted today"
 	} else {
-		challengeStatus = fmt.Sprintf("✅ Day %d: %s", dayNumber, todaysChallenge.Title)
+		challengeStatus = fmt.Sprintf("✅ Day %d: %s", dayNumber, chat.Escape(todaysChallenge.Title))
+	}
+
+	// Check if a track is running
+	var trackStatus string
+	if track, err := b.db.GetActiveTrack(today); err == nil {
+		trackStatus = fmt.Sprintf("🛤️ Track: %s - %d/%d posted (until %s)\n", chat.Escape(track.Name), track.Posted, track.Total, track.EndDate)
 	}
 
 	// Get leaderboard summary (top 3)
-	leaderboard, err := b.db.GetLeaderboard(3)
+	leaderboard, err := b.db.GetLeaderboard(3, b.config.HintPenalty)
 	var leaderboardStatus string
 	if err != nil || len(leaderboard) == 0 {
 		leaderboardStatus = "No submissions yet"
 	} else {
-		leaderboardStatus = fmt.Sprintf("Top: %s (%d solved)", leaderboard[0].FirstName, leaderboard[0].TotalSolved)
+		leaderboardStatus = fmt.Sprintf("Top: %s (%d solved)", chat.Escape(leaderboard[0].FirstName), leaderboard[0].TotalSolved)
 	}
 
 	// Get users who haven't submitted today
@@ -295,13 +499,15 @@ func (b *Bot) handleStatusCommand(message *tgbotapi.Message) {
 		"📅 Date: %s\n"+
 		"📊 Current Day Counter: %d\n"+
 		"🎯 Today's Challenge: %s\n"+
+		"%s"+
 		"📈 Leaderboard: %s\n"+
 		"📝 Submissions: %s\n\n"+
 		"⏰ Next challenge: Tomorrow 7:00 AM (Mon-Fri only)\n"+
 		"🎉 Weekend: No challenges",
-		time.Now().Format("January 2, 2006"),
+		b.now().Format("January 2, 2006"),
 		currentDay,
 		challengeStatus,
+		trackStatus,
 		leaderboardStatus,
 		submissionStatus)
 
@@ -311,8 +517,7 @@ func (b *Bot) handleStatusCommand(message *tgbotapi.Message) {
 // handleResetDayCommand handles the /resetday command
 func (b *Bot) handleResetDayCommand(message *tgbotapi.Message) {
 	// Check if user is admin (you can customize this logic)
-	if message.From.ID != b.config.TelegramGroupID && message.Chat.ID != b.config.TelegramGroupID {
-		b.sendMessage(message.Chat.ID, "❌ This command can only be used in the main group.")
+	if !b.isMainGroupMessage(message) {
 		return
 	}
 
@@ -327,26 +532,100 @@ func (b *Bot) handleResetDayCommand(message *tgbotapi.Message) {
 
 // sendMessage sends a message to a chat
 func (b *Bot) sendMessage(chatID int64, text string) {
-	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ParseMode = tgbotapi.ModeMarkdown
-
-	if _, err := b.api.Send(msg); err != nil {
+	if _, err := b.postMessage(chatID, text, nil); err != nil {
 		log.Printf("Error sending message: %v", err)
 	}
 }
 
-// PostDailyChallenge posts the daily challenge to the group
+// postMessage sends a Telegram message, with an optional inline keyboard, and returns its message ID
+func (b *Bot) postMessage(chatID int64, text string, keyboard *tgbotapi.InlineKeyboardMarkup) (int, error) {
+	return b.telegram.SendWithKeyboard(chatID, text, keyboard)
+}
+
+// editMessage replaces the text of a previously sent Telegram message. The inline keyboard
+// is replaced too, and removed when keyboard is nil.
+func (b *Bot) editMessage(chatID int64, messageID int, text string, keyboard *tgbotapi.InlineKeyboardMarkup) error {
+	return b.telegram.EditWithKeyboard(chatID, messageID, text, keyboard)
+}
+
+// PostDailyChallenge posts the daily challenge to the group. It returns ErrDaySkipped
+// without posting when the day was skipped.
 func (b *Bot) PostDailyChallenge() error {
-	// Get a random unused problem
-	problem, err := b.db.GetRandomUnusedProblem()
+	today := b.today()
+
+	skipped, err := b.db.IsDaySkipped(today)
+	if err != nil {
+		return fmt.Errorf("failed to check skipped days: %w", err)
+	}
+	if skipped {
+		log.Printf("Challenge for %s was skipped, not posting", today)
+		return ErrDaySkipped
+	}
+
+	problem, err := b.nextProblem(today)
+	if err != nil {
+		metrics.DailyPosts.Inc("error")
+		return err
+	}
+
+	if err := b.postDailyChallenge(problem); err != nil {
+		metrics.DailyPosts.Inc("error")
+		return err
+	}
+	return nil
+}
+
+// nextProblem picks the problem for the given date: a queued problem first, then the
+// next problem of the active track, and a random unused problem otherwise
+func (b *Bot) nextProblem(date string) (*models.Problem, error) {
+	problem, err := b.db.GetNextQueuedProblem(date)
+	if err == nil {
+		return problem, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get queued problem: %w", err)
+	}
+
+	track, err := b.db.GetActiveTrack(date)
+	if err == nil {
+		problem, err := b.db.GetNextTrackProblem(track.ID)
+		if err == nil {
+			return problem, nil
+		}
+		if err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to get next problem of track %s: %w", track.Name, err)
+		}
+		log.Printf("Track %s is finished, falling back to a random problem", track.Name)
+	} else if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get active track: %w", err)
+	}
+
+	problem, err = b.db.GetRandomUnusedProblem(date)
 	if err != nil {
-		return fmt.Errorf("failed to get random problem: %w", err)
+		return nil, fmt.Errorf("failed to get random problem: %w", err)
+	}
+
+	return problem, nil
+}
+
+// postDailyChallenge posts the given problem as today's challenge
+func (b *Bot) postDailyChallenge(problem *models.Problem) error {
+	today := b.today()
+
+	if _, err := b.db.GetDailyChallenge(today); err == nil {
+		return fmt.Errorf("today's challenge has already been posted, use /reroll or /pick to replace it")
 	}
 
 	// Mark problem as used
 	if err := b.db.MarkProblemAsUsed(problem.ID); err != nil {
 		return fmt.Errorf("failed to mark problem as used: %w", err)
 	}
+	if err := b.db.DequeueProblem(problem.ID); err != nil {
+		return fmt.Errorf("failed to remove problem from queue: %w", err)
+	}
+	if err := b.db.MarkTrackProblemPosted(problem.ID, today); err != nil {
+		return fmt.Errorf("failed to update track progress: %w", err)
+	}
 
 	// Get and increment day number
 	dayNumber, err := b.db.IncrementDayNumber()
@@ -355,7 +634,6 @@ func (b *Bot) PostDailyChallenge() error {
 	}
 
 	// Add to daily challenges
-	today := time.Now().Format("2006-01-02")
 	challenge := &models.DailyChallenge{
 		ProblemID: problem.ID,
 		Date:      today,
@@ -366,33 +644,71 @@ func (b *Bot) PostDailyChallenge() error {
 		return fmt.Errorf("failed to add daily challenge: %w", err)
 	}
 
-	// Create message with day number
-	messageText := fmt.Sprintf("🌅 **Daily LeetCode Challenge - Day %d** 🌅\n"+
-		"📅 %s\n\n"+
-		"📝 **%s**\n"+
-		"🏷️ Category: %s\n"+
-		"🔗 %s\n\n"+
-		"💪 Ready to solve it? Use /submit when you're done!\n"+
-		"Good luck everyone! 🍀",
-		dayNumber,
-		time.Now().Format("January 2, 2006"),
-		problem.Title,
-		problem.Category,
-		problem.URL)
+	// Send to group and remember the announcement so it can be edited later
+	if err := b.refreshDailyAnnouncement(today); err != nil {
+		log.Printf("Error sending daily announcement: %v", err)
+		metrics.DailyPosts.Inc("error")
+	} else {
+		metrics.DailyPosts.Inc("ok")
+		metrics.LastDailyPost.SetToCurrentTime()
+	}
+	if err := b.refreshProgressBoard(today); err != nil {
+		log.Printf("Error sending progress board: %v", err)
+	}
 
-	// Send to group
-	b.sendMessage(b.config.TelegramGroupID, messageText)
+	b.events.Publish(events.ChallengePosted,
+		fmt.Sprintf("🌅 Day %d: %s %s", dayNumber, problem.Title, problem.URL),
+		events.ChallengePostedData{DayNumber: dayNumber, Date: today, Problem: problem})
 
 	log.Printf("Posted daily challenge Day %d: %s", dayNumber, problem.Title)
 	return nil
 }
 
+// dailyChallengeText builds the daily challenge announcement for the given date and chat
+// platform, including the progress of the active track and who has solved it so far
+func (b *Bot) dailyChallengeText(date string, dayNumber int, problem *models.Problem, platform string) string {
+	track, err := b.db.GetActiveTrack(date)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Error getting active track: %v", err)
+	}
+	solvers, err := b.db.GetSolvers(date)
+	if err != nil {
+		log.Printf("Error getting solvers: %v", err)
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", date, b.location)
+	if err != nil {
+		log.Printf("Error parsing challenge date %s: %v", date, err)
+		day = b.now()
+	}
+
+	data := messages.DailyChallengeData{
+		DayNumber: dayNumber,
+		Date:      day,
+		Problem:   escapeProblem(problem),
+		Platform:  platform,
+	}
+	if track != nil {
+		escaped := *track
+		escaped.Name = chat.Escape(track.Name)
+		data.Track = &escaped
+	}
+	if judgePlatform := problemPlatform(problem); judgePlatform != judge.LeetCode {
+		data.Judge = judgePlatform.Name()
+	}
+	for _, solver := range solvers {
+		data.Solvers = append(data.Solvers, chat.Escape(solver.FirstName))
+	}
+
+	return b.messages.Render(b.language(platform), messages.DailyChallenge, data)
+}
+
 // SendReminder sends a reminder to users who haven't submitted
 func (b *Bot) SendReminder() error {
-	today := time.Now().Format("2006-01-02")
+	today := b.today()
 
-	// Get users who haven't submitted today
-	users, err := b.db.GetUsersWhoDidntSubmitToday(today)
+	// Get users who haven't submitted today and didn't skip it
+	users, err := b.db.GetUsersToRemind(today)
 	if err != nil {
 		return fmt.Errorf("failed to get users who didn't submit: %w", err)
 	}
@@ -408,52 +724,62 @@ func (b *Bot) SendReminder() error {
 		return fmt.Errorf("failed to get today's challenge: %w", err)
 	}
 
-	// Create reminder message with mentions
-	var mentions []string
-	for _, user := range users {
-		if user.Username != "" {
-			mentions = append(mentions, "@"+user.Username)
-		} else {
-			mentions = append(mentions, user.FirstName)
+	evening := b.now().Hour() != 15
+
+	// Send to every group, mentioning the members the way their platform does
+	var failed []string
+	for _, group := range b.groups {
+		members, err := b.groupMembers(group, users)
+		if err != nil {
+			log.Printf("Error getting %s members: %v", group.Platform.Name(), err)
+			failed = append(failed, group.Platform.Name())
+			continue
+		}
+		if len(members) == 0 {
+			continue
 		}
-	}
 
-	currentHour := time.Now().Hour()
-	var reminderEmoji string
-	var reminderTime string
+		var mentions []string
+		for _, member := range members {
+			mentions = append(mentions, group.Platform.Mention(member))
+		}
 
-	if currentHour == 15 {
-		reminderEmoji = "⏰"
-		reminderTime = "Afternoon"
-	} else {
-		reminderEmoji = "🌙"
-		reminderTime = "Evening"
+		messageText := b.messages.Render(b.language(group.Platform.Name()), messages.Reminder, messages.ReminderData{
+			Evening:   evening,
+			Mentions:  mentions,
+			DayNumber: dayNumber,
+			Problem:   escapeProblem(todaysChallenge),
+			Platform:  group.Platform.Name(),
+		})
+
+		if _, err := group.Platform.Send(group.ChatID, messageText); err != nil {
+			metrics.Reminders.Inc("error")
+			log.Printf("Error sending %s reminder: %v", group.Platform.Name(), err)
+			failed = append(failed, group.Platform.Name())
+			continue
+		}
+		metrics.Reminders.Inc("ok")
+	}
+	if len(failed) == len(b.groups) {
+		return fmt.Errorf("failed to send reminder to %s", strings.Join(failed, ", "))
 	}
 
-	messageText := fmt.Sprintf("%s **%s Reminder** %s\n\n"+
-		"Hey %s!\n\n"+
-		"Don't forget about today's LeetCode challenge (Day %d):\n"+
-		"📝 **%s**\n"+
-		"🔗 %s\n\n"+
-		"Use /submit when you're done! ⚡",
-		reminderEmoji, reminderTime, reminderEmoji,
-		strings.Join(mentions, ", "),
-		dayNumber,
-		todaysChallenge.Title,
-		todaysChallenge.URL)
-
-	// Send to group
-	b.sendMessage(b.config.TelegramGroupID, messageText)
+	b.events.Publish(events.ReminderSent,
+		fmt.Sprintf("⏰ Reminded %d members about Day %d: %s", len(users), dayNumber, todaysChallenge.Title),
+		events.ReminderSentData{DayNumber: dayNumber, Date: today, Problem: todaysChallenge, Users: users})
 
 	log.Printf("Sent reminder to %d users for Day %d", len(users), dayNumber)
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to send reminder to %s", strings.Join(failed, ", "))
+	}
 	return nil
 }
 
 func (b *Bot) CheckSubmissions() error {
-	today := time.Now().Format("2006-01-02")
+	today := b.today()
 
-	// Get users who haven't submitted today
-	users, err := b.db.GetUsersWhoDidntSubmitToday(today)
+	// Get users whose solve hasn't been verified today, including self-reported ones
+	users, err := b.db.GetUsersWithoutVerifiedSubmission(today)
 	if err != nil {
 		return fmt.Errorf("failed to get users who didn't submit: %w", err)
 	}
@@ -469,70 +795,188 @@ func (b *Bot) CheckSubmissions() error {
 		return fmt.Errorf("failed to get today's challenge: %w", err)
 	}
 
+	if _, ok := b.judges[problemPlatform(todaysChallenge)]; !ok {
+		log.Printf("Solves on %s can't be verified, only self-reported solves count for Day %d", problemPlatform(todaysChallenge).Name(), dayNumber)
+		return nil
+	}
+
+	newSolves := 0
 	for _, user := range users {
-		leetcodeProfile, err := b.db.GetLeetcodeProfile(user.ID)
+		solvedAt, err := b.acceptedSolve(user.ID, todaysChallenge, today)
 		if err != nil {
-			log.Printf("Error getting LeetCode profile for user %d: %v", user.ID, err)
+			log.Printf("Error checking the solve of user %d: %v", user.ID, err)
 			continue
 		}
 
-		submissions, err := leetcode.GetRecentACByUsername(leetcodeProfile.Username)
-		done := false
-		for _, submission := range submissions {
-			if submission.Title == todaysChallenge.Title && submission.Timestamp.Format("2006-01-02") == today {
-				done = true
-				break
-			}
-		}
-
-		if done {
-			// Announce submission in group
-			var mention string
-			if user.Username != "" {
-				mention = "@" + user.Username
-			} else {
-				mention = user.FirstName
-			}
+		if solvedAt != nil {
 			submission := &models.Submission{
 				UserID:    user.ID,
 				ProblemID: todaysChallenge.ID,
 				Date:      today,
+				Verified:  true,
+				SolvedAt:  solvedAt,
 			}
-			err = b.db.AddSubmission(submission)
-			if err != nil {
+			if _, err := b.recordSubmission(submission); err != nil {
 				log.Printf("Error adding submission for user %d: %v", user.ID, err)
 				continue
 			}
+			newSolves++
+		}
+	}
+
+	if newSolves > 0 {
+		log.Printf("Verified %d new solves for Day %d", newSolves, dayNumber)
+	}
+	return nil
+}
 
-			messageText := fmt.Sprintf("🎉 %s has just submitted today's challenge (Day %d):\n\n", mention, dayNumber)
+// recordSubmission stores a solve of today's challenge and refreshes the daily announcement,
+// and the progress board for verified solves, then checks for new achievements and sends the
+// submission.recorded event. It reports
+// whether this is the user's first submission of the day.
+func (b *Bot) recordSubmission(submission *models.Submission) (bool, error) {
+	hasSubmitted, err := b.db.HasUserSubmittedToday(submission.UserID, submission.Date)
+	if err != nil {
+		return false, err
+	}
 
-			b.sendMessage(b.config.TelegramGroupID, messageText)
+	if err := b.db.AddSubmission(submission); err != nil {
+		return false, err
+	}
+
+	if !hasSubmitted {
+		if err := b.refreshDailyAnnouncement(submission.Date); err != nil {
+			log.Printf("Error refreshing daily announcement: %v", err)
 		}
 	}
-	return nil
+	if submission.Verified {
+		if err := b.refreshProgressBoard(submission.Date); err != nil {
+			log.Printf("Error refreshing progress board: %v", err)
+		}
+	}
+	if err := b.checkAchievements(submission.UserID, submission.Date); err != nil {
+		log.Printf("Error checking achievements: %v", err)
+	}
+	b.publishSubmission(submission)
+
+	return !hasSubmitted, nil
 }
 
-func (b *Bot) handleRegisterLeetcodeProfile(message *tgbotapi.Message) error {
-	userID := message.From.ID
-	username := strings.TrimSpace(message.CommandArguments())
-	if username == "" {
-		b.sendMessage(message.Chat.ID, "❌ Please provide your LeetCode username. Usage: /register <leetcode_username>")
-		return nil
+// handleRegisterLeetcodeProfile handles the /register command
+func (b *Bot) handleRegisterLeetcodeProfile(message *tgbotapi.Message) {
+	b.sendReply(message, b.registerLeetcode(message.From.ID, strings.Fields(message.CommandArguments())))
+}
+
+// registerLeetcode links a LeetCode account to a user from the arguments <username> [com|cn].
+// The username must exist on LeetCode and not be verified by someone else. Registering again
+// changes the account. The link only counts once it's verified with /verify.
+func (b *Bot) registerLeetcode(userID int64, args []string) chat.Reply {
+	if len(args) == 0 || len(args) > 2 {
+		return chat.Reply{Text: "❌ Please provide your LeetCode username. Usage: /register <leetcode_username> [com|cn]"}
+	}
+	username := strings.TrimPrefix(args[0], "@")
+
+	site := leetcode.SiteGlobal
+	if len(args) == 2 {
+		var err error
+		if site, err = leetcode.ParseSite(args[1]); err != nil {
+			return chat.Reply{Text: "❌ Unknown LeetCode site, use com for leetcode.com or cn for leetcode.cn."}
+		}
 	}
 
 	// Check if user is already registered
-	existingProfile, err := b.db.GetLeetcodeProfile(userID)
-	if err != nil && existingProfile != nil {
-		b.sendMessage(message.Chat.ID, "❌ You have already registered your LeetCode username.")
-		return nil
+	existingLink, err := b.db.GetLeetcodeLink(userID)
+	if err == nil && strings.EqualFold(existingLink.LeetCodeUsername, username) && existingLink.Site == string(site) {
+		if existingLink.Verified {
+			return chat.Reply{Text: fmt.Sprintf("✅ You have already registered your LeetCode username: %s", chat.Escape(existingLink.LeetCodeUsername))}
+		}
+		return chat.Reply{
+			Text:    "🔐 Your LeetCode username still needs to be verified.",
+			Private: verificationInstructions(site, existingLink.LeetCodeUsername, existingLink.VerificationToken),
+		}
+	}
+
+	// Don't let anyone claim a username that another member has verified on the same site
+	ownerID, ownerVerified, err := b.db.GetLeetcodeProfileOwner(string(site), username)
+	if err == nil && ownerID != userID && ownerVerified {
+		return chat.Reply{Text: fmt.Sprintf("❌ The LeetCode username %s is already registered by another member.", chat.Escape(username))}
+	}
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Error checking LeetCode username owner: %v", err)
+		return chat.Reply{Text: "❌ An error occurred while registering your LeetCode username."}
+	}
+
+	// Make sure the username exists, and use LeetCode's spelling of it
+	profile, err := leetcode.GetUserProfile(site, username)
+	if err != nil {
+		log.Printf("Error validating LeetCode username %s on %s: %v", username, site, err)
+		return chat.Reply{Text: fmt.Sprintf("❌ Could not find the user %s on %s. Please check the spelling and try again.", chat.Escape(username), site)}
 	}
+	username = profile.Username
 
-	err = b.db.RegisterLeetcodeProfile(userID, username)
+	token, err := newVerificationToken()
+	if err != nil {
+		log.Printf("Error generating verification token: %v", err)
+		return chat.Reply{Text: "❌ An error occurred while registering your LeetCode username."}
+	}
+
+	err = b.db.RegisterLeetcodeProfile(userID, username, string(site), token)
 	if err != nil {
 		log.Printf("Error registering LeetCode profile: %v", err)
-		b.sendMessage(message.Chat.ID, "❌ An error occurred while registering your LeetCode username.")
-		return err
+		return chat.Reply{Text: "❌ An error occurred while registering your LeetCode username."}
 	}
-	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Successfully registered your LeetCode username: %s", username))
-	return nil
+
+	headline := fmt.Sprintf("✅ Registered your %s username: **%s**", site, chat.Escape(username))
+	if existingLink != nil {
+		headline = fmt.Sprintf("✅ Changed your LeetCode username from %s (%s) to **%s** (%s)",
+			chat.Escape(existingLink.LeetCodeUsername), existingLink.Site, chat.Escape(username), site)
+	}
+	confirmation := fmt.Sprintf("%s\n"+
+		"📊 Solved on LeetCode: %d (🟢 %d / 🟡 %d / 🔴 %d)\n\n%s",
+		headline, profile.TotalSolved, profile.EasySolved, profile.MediumSolved, profile.HardSolved,
+		verificationInstructions(site, username, token))
+	return chat.Reply{Text: "✅ LeetCode username registered, now it needs to be verified.", Private: confirmation}
+}
+
+// handleUnregisterCommand handles the /unregister command for removing a LeetCode username
+func (b *Bot) handleUnregisterCommand(message *tgbotapi.Message) {
+	removed, err := b.db.UnregisterLeetcodeProfile(message.From.ID)
+	if err != nil {
+		log.Printf("Error unregistering LeetCode profile: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while removing your LeetCode username.")
+		return
+	}
+	if !removed {
+		b.sendMessage(message.Chat.ID, "❌ You haven't registered a LeetCode username.")
+		return
+	}
+
+	b.confirmInPrivate(message, "✅ Your LeetCode username has been removed. Your solves will no longer be verified automatically.",
+		"✅ LeetCode username removed.")
+}
+
+// sendReply answers a Telegram command with a reply, sending its private part with confirmInPrivate
+func (b *Bot) sendReply(message *tgbotapi.Message, reply chat.Reply) {
+	if reply.Private != "" {
+		b.confirmInPrivate(message, reply.Private, reply.Text)
+		return
+	}
+	b.sendMessage(message.Chat.ID, reply.Text)
+}
+
+// confirmInPrivate sends a confirmation to the user in private chat. When the command was
+// sent in a group, the group gets a short acknowledgement, or the full confirmation if the
+// user hasn't started a private chat with the bot.
+func (b *Bot) confirmInPrivate(message *tgbotapi.Message, confirmation, groupAck string) {
+	if message.Chat.IsPrivate() {
+		b.sendMessage(message.Chat.ID, confirmation)
+		return
+	}
+
+	if _, err := b.postMessage(message.From.ID, confirmation, nil); err != nil {
+		log.Printf("Error sending confirmation to user %d: %v", message.From.ID, err)
+		b.sendMessage(message.Chat.ID, confirmation)
+		return
+	}
+	b.sendMessage(message.Chat.ID, groupAck+" Details sent in private chat.")
 }
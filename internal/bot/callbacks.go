@@ -0,0 +1,209 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"leetcode-telegram-bot/internal/chat"
+	"leetcode-telegram-bot/internal/judge"
+	"leetcode-telegram-bot/internal/leetcode"
+	"leetcode-telegram-bot/internal/messages"
+	"leetcode-telegram-bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Callback actions of the daily challenge buttons. The callback data is "<action>:<date>"
+// so that buttons of past announcements can be told apart from today's.
+const (
+	callbackSolved = "solved"
+	callbackHint   = "hint"
+	callbackSkip   = "skip"
+)
+
+// dailyChallengeKeyboard builds the inline buttons shown under the daily challenge announcement
+func dailyChallengeKeyboard(date string, problem *models.Problem) *tgbotapi.InlineKeyboardMarkup {
+	discussURL := problem.URL
+	if slug := leetcode.SlugFromURL(problem.URL); slug != "" && problemPlatform(problem) == judge.LeetCode {
+		discussURL = leetcode.SiteFromURL(problem.URL).DiscussURL(slug)
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ I solved it", callbackSolved+":"+date),
+			tgbotapi.NewInlineKeyboardButtonData("💡 Hint", callbackHint+":"+date),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL("💬 Discuss", discussURL),
+			tgbotapi.NewInlineKeyboardButtonData("😴 Skip for today", callbackSkip+":"+date),
+		),
+	)
+	return &keyboard
+}
+
+// refreshDailyAnnouncement edits the daily challenge announcement of the given date to
+// reflect its current problem and solvers, in the main group and in the chats on other
+// platforms. If the announcement is unknown or can no longer be edited, a new one is
+// posted and remembered instead.
+func (b *Bot) refreshDailyAnnouncement(date string) error {
+	challenge, err := b.db.GetDailyChallenge(date)
+	if err != nil {
+		return fmt.Errorf("failed to get daily challenge: %w", err)
+	}
+
+	problem, dayNumber, err := b.db.GetTodaysChallengeWithDay(date)
+	if err != nil {
+		return fmt.Errorf("failed to get daily challenge problem: %w", err)
+	}
+
+	b.refreshGroupMessages(date, announcementKind, func(platform string) string {
+		return b.dailyChallengeText(date, dayNumber, problem, platform)
+	})
+
+	text := b.dailyChallengeText(date, dayNumber, problem, chat.PlatformTelegram)
+	keyboard := dailyChallengeKeyboard(date, problem)
+
+	if challenge.MessageID != 0 {
+		err := b.editMessage(b.config.TelegramGroupID, challenge.MessageID, text, keyboard)
+		if err == nil {
+			return nil
+		}
+		log.Printf("Error editing announcement, posting a new one: %v", err)
+	}
+
+	messageID, err := b.postMessage(b.config.TelegramGroupID, text, keyboard)
+	if err != nil {
+		return fmt.Errorf("failed to send announcement: %w", err)
+	}
+	if err := b.db.SetDailyChallengeMessageID(date, messageID); err != nil {
+		return fmt.Errorf("failed to save announcement message ID: %w", err)
+	}
+
+	return nil
+}
+
+// handleCallbackQuery processes presses of the daily challenge buttons
+func (b *Bot) handleCallbackQuery(query *tgbotapi.CallbackQuery) {
+	user := &models.User{
+		ID:        query.From.ID,
+		Username:  query.From.UserName,
+		FirstName: query.From.FirstName,
+		LastName:  query.From.LastName,
+	}
+	if err := b.db.AddUser(user); err != nil {
+		log.Printf("Error saving user: %v", err)
+	}
+
+	action, date, _ := strings.Cut(query.Data, ":")
+	if date != b.today() {
+		b.answerCallback(query.ID, "⌛ This challenge is over.", false)
+		return
+	}
+
+	switch action {
+	case callbackSolved:
+		b.handleSolvedCallback(query, date)
+	case callbackHint:
+		b.handleHintCallback(query, date)
+	case callbackSkip:
+		b.handleSkipCallback(query, date)
+	default:
+		b.answerCallback(query.ID, "", false)
+	}
+}
+
+// handleSolvedCallback records a solve from the "I solved it" button
+func (b *Bot) handleSolvedCallback(query *tgbotapi.CallbackQuery, date string) {
+	text, alert := b.submitSolve(query.From.ID, date, b.language(chat.PlatformTelegram))
+	b.answerCallback(query.ID, text, alert)
+}
+
+// submitSolve records a user's solve of the challenge of the given date. Users with a
+// verified account on the problem's judge are verified right away, others are self-reported.
+// It returns the answer for the user, in the given language, and whether it reports a problem.
+func (b *Bot) submitSolve(userID int64, date, language string) (string, bool) {
+	hasSubmitted, err := b.db.HasUserSubmittedToday(userID, date)
+	if err != nil {
+		log.Printf("Error checking submission: %v", err)
+		return "❌ An error occurred while checking your submission.", true
+	}
+	if hasSubmitted {
+		return b.messages.Render(language, messages.AlreadySolved, nil), false
+	}
+
+	problem, err := b.db.GetTodaysChallenge(date)
+	if err != nil {
+		log.Printf("Error getting today's challenge: %v", err)
+		return "❌ No challenge available for today yet.", true
+	}
+
+	submission := &models.Submission{
+		UserID:    userID,
+		ProblemID: problem.ID,
+		Date:      date,
+	}
+	solvedAt, err := b.acceptedSolve(userID, problem, date)
+	if err != nil {
+		log.Printf("Error checking the solve of user %d: %v", userID, err)
+	}
+	if solvedAt != nil {
+		submission.Verified = true
+		submission.SolvedAt = solvedAt
+	}
+
+	if _, err := b.recordSubmission(submission); err != nil {
+		log.Printf("Error adding submission: %v", err)
+		return "❌ An error occurred while submitting.", true
+	}
+
+	return b.messages.Render(language, messages.Solved, messages.SolvedData{
+		Verified: submission.Verified,
+		Judge:    problemPlatform(problem).Name(),
+	}), false
+}
+
+// handleHintCallback reveals the user's next hint for today's problem. Hints too long
+// for an alert are sent in private chat instead.
+func (b *Bot) handleHintCallback(query *tgbotapi.CallbackQuery, date string) {
+	hint, err := b.nextHint(query.From.ID, date)
+	if err != nil {
+		log.Printf("Error getting hint: %v", err)
+		b.answerCallback(query.ID, "❌ No challenge available for today yet.", true)
+		return
+	}
+
+	if fitsAlert(hint) {
+		b.answerCallback(query.ID, hint, true)
+		return
+	}
+
+	if _, err := b.postMessage(query.From.ID, hint, nil); err != nil {
+		log.Printf("Error sending hint to user %d: %v", query.From.ID, err)
+		b.answerCallback(query.ID, "🔒 This hint is too long for a popup. Start a private chat with me and tap Hint again.", true)
+		return
+	}
+	b.answerCallback(query.ID, "💡 I sent you the hint in private chat.", false)
+}
+
+// handleSkipCallback opts the user out of today's reminders
+func (b *Bot) handleSkipCallback(query *tgbotapi.CallbackQuery, date string) {
+	if err := b.db.AddReminderOptOut(query.From.ID, date); err != nil {
+		log.Printf("Error saving reminder opt-out: %v", err)
+		b.answerCallback(query.ID, "❌ An error occurred, please try again.", true)
+		return
+	}
+
+	b.answerCallback(query.ID, "😴 Got it, no reminders for you today. See you tomorrow!", false)
+}
+
+// answerCallback acknowledges a button press, optionally showing the text as an alert.
+// Alerts can't be formatted, so the text is shown without its formatting.
+func (b *Bot) answerCallback(queryID, text string, alert bool) {
+	callback := tgbotapi.NewCallback(queryID, chat.PlainText(text))
+	callback.ShowAlert = alert
+
+	if _, err := b.api.Request(callback); err != nil {
+		log.Printf("Error answering callback query: %v", err)
+	}
+}
@@ -0,0 +1,320 @@
+package bot
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"leetcode-telegram-bot/internal/chat"
+	"leetcode-telegram-bot/internal/messages"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Group setting keys for the digests. Both digests are on in every group unless turned off
+// with /digest.
+const (
+	settingDailyDigest  = "daily_digest"
+	settingWeeklyDigest = "weekly_digest"
+)
+
+// solveTimeBuckets are the upper bounds of the solve-time distribution in the daily digest
+var solveTimeBuckets = []struct {
+	label string
+	limit time.Duration
+}{
+	{"< 1h", time.Hour},
+	{"1-3h", 3 * time.Hour},
+	{"3-6h", 6 * time.Hour},
+	{"6-12h", 12 * time.Hour},
+	{"12h+", 0},
+}
+
+// handleDigestCommand handles the /digest command for toggling or posting the digests
+// of the Telegram group or of the Slack or Discord channel
+func (b *Bot) handleDigestCommand(message *tgbotapi.Message) {
+	if !b.isGroupAdminMessage(message) {
+		return
+	}
+
+	usage := "❌ Usage: /digest [telegram|slack|discord] <daily|weekly> [on|off]"
+	args := strings.Fields(strings.ToLower(message.CommandArguments()))
+	if len(args) == 0 {
+		var responseText strings.Builder
+		responseText.WriteString("📰 **Digests**\n\n")
+		for _, group := range b.groups {
+			platform := group.Platform.Name()
+			responseText.WriteString(fmt.Sprintf("• %s: daily %s, weekly %s\n", platform,
+				b.digestSetting(settingDailyDigest, platform), b.digestSetting(settingWeeklyDigest, platform)))
+		}
+		responseText.WriteString(fmt.Sprintf("\nEvery group gets them on the same schedules: daily `%s`, weekly `%s`, "+
+			"set with `DAILY_DIGEST_SCHEDULE` and `WEEKLY_DIGEST_SCHEDULE`.\n\n"+
+			"Usage: /digest [telegram|slack|discord] <daily|weekly> [on|off]. Without on/off the digest is posted right away.",
+			b.config.DailyDigestSchedule, b.config.WeeklyDigestSchedule))
+		b.sendMessage(message.Chat.ID, responseText.String())
+		return
+	}
+
+	group := b.groups[0]
+	for _, other := range b.groups {
+		if other.Platform.Name() == args[0] {
+			group = other
+			args = args[1:]
+			break
+		}
+	}
+	if len(args) == 0 || len(args) > 2 {
+		b.sendMessage(message.Chat.ID, usage)
+		return
+	}
+	platform := group.Platform.Name()
+
+	var key string
+	switch args[0] {
+	case "daily":
+		key = settingDailyDigest
+	case "weekly":
+		key = settingWeeklyDigest
+	default:
+		b.sendMessage(message.Chat.ID, usage)
+		return
+	}
+
+	if len(args) == 1 {
+		name, data, err := b.digest(key)
+		if err != nil {
+			log.Printf("Error in digest command: %v", err)
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error posting digest: %v", err))
+			return
+		}
+		if data == nil {
+			b.sendMessage(message.Chat.ID, "📭 There is nothing to put in that digest yet.")
+			return
+		}
+		if _, err := group.Platform.Send(group.ChatID, b.messages.Render(b.language(platform), name, data)); err != nil {
+			log.Printf("Error in digest command: %v", err)
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error posting digest: %v", err))
+		}
+		return
+	}
+
+	if args[1] != "on" && args[1] != "off" {
+		b.sendMessage(message.Chat.ID, usage)
+		return
+	}
+	if err := b.db.SetGroupSetting(b.config.TelegramGroupID, platformSetting(key, platform), args[1]); err != nil {
+		log.Printf("Error saving digest setting: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while saving the setting.")
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ The %s digest on %s is now %s.", args[0], platform, args[1]))
+}
+
+// digestSetting returns whether the digest with the given setting key is "on" or "off" for a
+// platform's group
+func (b *Bot) digestSetting(key, platform string) string {
+	enabled, err := b.db.GetGroupSetting(b.config.TelegramGroupID, platformSetting(key, platform), "on")
+	if err != nil {
+		log.Printf("Error getting digest setting: %v", err)
+	}
+	return enabled
+}
+
+// SendDailyDigest posts the end-of-day digest to every group unless it has been turned off
+func (b *Bot) SendDailyDigest() error {
+	return b.sendDigest(settingDailyDigest)
+}
+
+// SendWeeklyDigest posts the end-of-week digest to every group unless it has been turned off
+func (b *Bot) SendWeeklyDigest() error {
+	return b.sendDigest(settingWeeklyDigest)
+}
+
+// sendDigest posts the digest with the given setting key to every group that hasn't turned
+// it off, in each group's language
+func (b *Bot) sendDigest(key string) error {
+	var groups []chat.Group
+	for _, group := range b.groups {
+		if b.digestSetting(key, group.Platform.Name()) == "on" {
+			groups = append(groups, group)
+		}
+	}
+	if len(groups) == 0 {
+		log.Printf("Digest %s is turned off", key)
+		return nil
+	}
+
+	name, data, err := b.digest(key)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		log.Printf("Nothing to report, skipping digest %s", key)
+		return nil
+	}
+	return b.sendToGroups(groups, func(platform string) string {
+		return b.messages.Render(b.language(platform), name, data)
+	})
+}
+
+// digest gathers the data of the digest with the given setting key, along with the name of
+// the template it is rendered with. The data is nil when there is nothing to report.
+func (b *Bot) digest(key string) (string, interface{}, error) {
+	if key == settingDailyDigest {
+		data, err := b.dailyDigest()
+		if err != nil || data == nil {
+			return "", nil, err
+		}
+		return messages.DailyDigest, data, nil
+	}
+
+	data, err := b.weeklyDigest()
+	if err != nil || data == nil {
+		return "", nil, err
+	}
+	return messages.WeeklyDigest, data, nil
+}
+
+// dailyDigest gathers who solved and who missed today's challenge, the solve-time
+// distribution and what is scheduled next. It returns nil when there was no challenge today.
+func (b *Bot) dailyDigest() (*messages.DailyDigestData, error) {
+	today := b.today()
+
+	challenge, err := b.db.GetDailyChallenge(today)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get today's challenge: %w", err)
+	}
+	problem, err := b.db.GetTodaysChallenge(today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get today's challenge: %w", err)
+	}
+	solves, err := b.db.GetSolves(today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get today's solves: %w", err)
+	}
+	missed, err := b.db.GetUsersWhoDidntSubmitToday(today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users who didn't submit: %w", err)
+	}
+
+	data := messages.DailyDigestData{
+		DayNumber: challenge.DayNumber,
+		Problem:   escapeProblem(problem),
+		Next:      b.nextChallenge(),
+	}
+	for _, solve := range solves {
+		data.Solved = append(data.Solved, messages.DigestSolver{Name: chat.Escape(solve.FirstName), Verified: solve.Verified})
+	}
+	for _, user := range missed {
+		data.Missed = append(data.Missed, chat.Escape(user.FirstName))
+	}
+
+	if len(solves) > 0 {
+		counts := make([]int, len(solveTimeBuckets))
+		for _, solve := range solves {
+			elapsed := solve.SolvedAt.Sub(challenge.PostedAt)
+			if solve.SolveSeconds != nil {
+				elapsed = time.Duration(*solve.SolveSeconds) * time.Second
+			}
+			for i, bucket := range solveTimeBuckets {
+				if bucket.limit == 0 || elapsed < bucket.limit {
+					counts[i]++
+					break
+				}
+			}
+		}
+		for i, bucket := range solveTimeBuckets {
+			data.SolveTimes = append(data.SolveTimes, messages.SolveTimeBucket{Label: bucket.label, Count: counts[i]})
+		}
+	}
+
+	return &data, nil
+}
+
+// nextChallenge describes what will be posted on the next challenge day
+func (b *Bot) nextChallenge() messages.NextChallenge {
+	next := b.now().AddDate(0, 0, 1)
+	for next.Weekday() == time.Saturday || next.Weekday() == time.Sunday {
+		next = next.AddDate(0, 0, 1)
+	}
+	date := next.Format("2006-01-02")
+	data := messages.NextChallenge{Date: next}
+
+	if skipped, err := b.db.IsDaySkipped(date); err == nil && skipped {
+		data.Cancelled = true
+		return data
+	}
+
+	if problem, err := b.db.GetNextQueuedProblem(date); err == nil {
+		data.Problem = escapeProblem(problem)
+		return data
+	} else if err != sql.ErrNoRows {
+		log.Printf("Error getting queued problem: %v", err)
+	}
+
+	if track, err := b.db.GetActiveTrack(date); err == nil && track.Posted < track.Total {
+		escaped := *track
+		escaped.Name = chat.Escape(track.Name)
+		data.Track = &escaped
+		data.TrackProblem = track.Posted + 1
+	}
+
+	return data
+}
+
+// weeklyDigest gathers the week's problems, top solvers and participation rate. It returns
+// nil when no challenge was posted this week.
+func (b *Bot) weeklyDigest() (*messages.WeeklyDigestData, error) {
+	now := b.now()
+	monday := now.AddDate(0, 0, -int((now.Weekday()+6)%7))
+	from := monday.Format("2006-01-02")
+	to := monday.AddDate(0, 0, 6).Format("2006-01-02")
+
+	history, err := b.db.GetChallengeHistory(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get this week's challenges: %w", err)
+	}
+	if len(history) == 0 {
+		return nil, nil
+	}
+	leaderboard, err := b.db.GetLeaderboardBetween(from, to, 5, b.config.HintPenalty)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get this week's leaderboard: %w", err)
+	}
+	members, err := b.db.CountUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	data := messages.WeeklyDigestData{
+		From:       monday,
+		To:         monday.AddDate(0, 0, 6),
+		Members:    members,
+		ShowPoints: b.config.HintPenalty > 0,
+	}
+	for _, entry := range history {
+		entry.Title = chat.Escape(entry.Title)
+		data.Challenges = append(data.Challenges, entry)
+		data.Solves += entry.Solvers
+	}
+	for i, entry := range leaderboard {
+		data.TopSolvers = append(data.TopSolvers, messages.LeaderboardEntry{
+			Rank:   strconv.Itoa(i + 1),
+			Name:   chat.Escape(entry.FirstName),
+			Solved: entry.TotalSolved,
+			Points: entry.Points,
+		})
+	}
+	if members > 0 {
+		data.Participation = data.Solves * 100 / (members * len(history))
+	}
+
+	return &data, nil
+}
@@ -0,0 +1,176 @@
+package bot
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"leetcode-telegram-bot/internal/chat"
+	"leetcode-telegram-bot/internal/messages"
+	"leetcode-telegram-bot/internal/metrics"
+	"leetcode-telegram-bot/internal/models"
+)
+
+// Kinds under which messages posted to the chats on other platforms are remembered, so
+// that they can be edited later
+const (
+	announcementKind = "announcement"
+	boardKind        = "board"
+)
+
+// handleChatCommand answers a /leetcode command sent from Slack or Discord. The sender
+// becomes a member the first time, the same way Telegram users do.
+func (b *Bot) handleChatCommand(command chat.Command) chat.Reply {
+	user, err := b.db.GetOrCreateChatUser(command.Platform, command.User.ID, command.User.Username, command.User.Name)
+	if err != nil {
+		log.Printf("Error saving %s user %s: %v", command.Platform, command.User.ID, err)
+		return chat.Reply{Text: "❌ An error occurred, please try again later."}
+	}
+
+	language := b.language(command.Platform)
+	name := command.Name
+	var reply chat.Reply
+	switch name {
+	case "", "help":
+		name = "help"
+		reply = chat.Reply{Text: b.messages.Render(language, messages.ChatHelp, nil)}
+	case "today":
+		reply = b.todayReply(command.Platform)
+	case "solved":
+		text, _ := b.submitSolve(user.ID, b.today(), language)
+		reply = chat.Reply{Private: text}
+	case "leaderboard", "leaderboards":
+		name = "leaderboards"
+		text, err := b.leaderboardText(language)
+		if err != nil {
+			log.Printf("Error getting leaderboard: %v", err)
+			text = "❌ An error occurred while fetching the leaderboard."
+		}
+		reply = chat.Reply{Text: text}
+	case "register":
+		reply = b.registerLeetcode(user.ID, strings.Fields(command.Args))
+	case "verify":
+		reply = b.verifyLeetcode(user.ID)
+	default:
+		name = "unknown"
+		reply = chat.Reply{Private: "Unknown command. Use /leetcode help to see available commands."}
+	}
+	metrics.Commands.Inc(name)
+	return reply
+}
+
+// todayReply shows today's challenge on a chat platform
+func (b *Bot) todayReply(platform string) chat.Reply {
+	today := b.today()
+	problem, dayNumber, err := b.db.GetTodaysChallengeWithDay(today)
+	if err == sql.ErrNoRows {
+		return chat.Reply{Text: "❌ No challenge available for today yet."}
+	}
+	if err != nil {
+		log.Printf("Error getting today's challenge: %v", err)
+		return chat.Reply{Text: "❌ An error occurred while fetching today's challenge."}
+	}
+	return chat.Reply{Text: b.dailyChallengeText(today, dayNumber, problem, platform)}
+}
+
+// refreshGroupMessages posts or edits the message of a kind for a date, e.g. the daily
+// announcement, in the chats on platforms other than Telegram, with the text for each
+// platform. Failures are only logged so they never hold up the main group.
+func (b *Bot) refreshGroupMessages(date, kind string, text func(platform string) string) {
+	for _, group := range b.groups {
+		platform := group.Platform.Name()
+		if platform == chat.PlatformTelegram {
+			continue
+		}
+
+		messageText := text(platform)
+		messageID, err := b.db.GetGroupMessageID(platform, group.ChatID, date, kind)
+		if err == nil {
+			err := group.Platform.Edit(group.ChatID, messageID, messageText)
+			if err == nil {
+				continue
+			}
+			log.Printf("Error editing %s %s, posting a new one: %v", platform, kind, err)
+		} else if err != sql.ErrNoRows {
+			log.Printf("Error getting %s %s: %v", platform, kind, err)
+		}
+
+		messageID, err = group.Platform.Send(group.ChatID, messageText)
+		if err != nil {
+			log.Printf("Error sending %s %s: %v", platform, kind, err)
+			continue
+		}
+		if err := b.db.SetGroupMessageID(platform, group.ChatID, date, kind, messageID); err != nil {
+			log.Printf("Error saving %s %s message ID: %v", platform, kind, err)
+		}
+	}
+}
+
+// sendToGroups posts a message to the given groups, with the text for each platform. A group
+// that can't be reached doesn't keep the message from the others; the error names them all.
+func (b *Bot) sendToGroups(groups []chat.Group, text func(platform string) string) error {
+	var failed []string
+	for _, group := range groups {
+		platform := group.Platform.Name()
+		if _, err := group.Platform.Send(group.ChatID, text(platform)); err != nil {
+			log.Printf("Error sending to %s: %v", platform, err)
+			failed = append(failed, platform)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to send to %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// editGroupMessages replaces the text of the messages of a kind posted for a date to the
+// chats on platforms other than Telegram. Chats without such a message are left alone.
+func (b *Bot) editGroupMessages(date, kind, text string) {
+	for _, group := range b.groups {
+		platform := group.Platform.Name()
+		if platform == chat.PlatformTelegram {
+			continue
+		}
+
+		messageID, err := b.db.GetGroupMessageID(platform, group.ChatID, date, kind)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			log.Printf("Error getting %s %s: %v", platform, kind, err)
+			continue
+		}
+		if err := group.Platform.Edit(group.ChatID, messageID, text); err != nil {
+			log.Printf("Error editing %s %s: %v", platform, kind, err)
+		}
+	}
+}
+
+// groupMembers picks the users that belong to a group's platform, as that platform knows them.
+// Telegram users are the ones with positive IDs, members from other platforms have an identity.
+func (b *Bot) groupMembers(group chat.Group, users []models.User) ([]chat.User, error) {
+	platform := group.Platform.Name()
+	var members []chat.User
+
+	if platform == chat.PlatformTelegram {
+		for _, user := range users {
+			if user.ID > 0 {
+				members = append(members, chat.User{ID: strconv.FormatInt(user.ID, 10), Username: user.Username, Name: user.FirstName})
+			}
+		}
+		return members, nil
+	}
+
+	identities, err := b.db.GetChatIdentities(platform)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if id, ok := identities[user.ID]; ok {
+			members = append(members, chat.User{ID: id, Username: user.Username, Name: user.FirstName})
+		}
+	}
+	return members, nil
+}
@@ -0,0 +1,150 @@
+package bot
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"leetcode-telegram-bot/internal/chat"
+	"leetcode-telegram-bot/internal/judge"
+	"leetcode-telegram-bot/internal/leetcode"
+	"leetcode-telegram-bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// maxAlertLength is the longest text Telegram shows in a callback alert
+const maxAlertLength = 200
+
+// handleHintCommand handles the /hint command. Hints are sent in private chat so the
+// rest of the group isn't spoiled.
+func (b *Bot) handleHintCommand(message *tgbotapi.Message) {
+	today := b.today()
+
+	hint, err := b.nextHint(message.From.ID, today)
+	if err != nil {
+		log.Printf("Error getting hint: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ No challenge available for today yet.")
+		return
+	}
+
+	if message.Chat.IsPrivate() {
+		b.sendMessage(message.Chat.ID, hint)
+		return
+	}
+
+	if _, err := b.postMessage(message.From.ID, hint, nil); err != nil {
+		log.Printf("Error sending hint to user %d: %v", message.From.ID, err)
+		b.sendMessage(message.Chat.ID, "🔒 I send hints in private chat. Start a chat with me first, then try /hint again.")
+		return
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("💡 %s, I sent you a hint in private chat.", chat.Escape(message.From.FirstName)))
+}
+
+// handleAddHintCommand handles the /addhint <slug> <text> command for adding a hint to a problem
+func (b *Bot) handleAddHintCommand(message *tgbotapi.Message) {
+	if !b.isGroupAdminMessage(message) {
+		return
+	}
+
+	slug, text, _ := strings.Cut(strings.TrimSpace(message.CommandArguments()), " ")
+	text = strings.TrimSpace(text)
+	if slug == "" || text == "" {
+		b.sendMessage(message.Chat.ID, "❌ Usage: /addhint <slug> <hint text>")
+		return
+	}
+
+	problem, err := b.db.GetProblemBySlug(slug)
+	if err == sql.ErrNoRows {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Problem %s was not found.", chat.Escape(slug)))
+		return
+	}
+	if err != nil {
+		log.Printf("Error getting problem %s: %v", slug, err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while looking up the problem.")
+		return
+	}
+
+	if err := b.db.AddProblemHint(problem.ID, text); err != nil {
+		log.Printf("Error adding hint: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while saving the hint.")
+		return
+	}
+
+	hints, err := b.db.GetProblemHints(problem.ID)
+	if err != nil {
+		log.Printf("Error getting hints: %v", err)
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Hint %d added to **%s**.", len(hints), chat.Escape(problem.Title)))
+}
+
+// nextHint reveals the user's next hint for the challenge of the given date and records
+// the new hint level. Once all hints are used, the last one is repeated.
+func (b *Bot) nextHint(userID int64, date string) (string, error) {
+	problem, err := b.db.GetTodaysChallenge(date)
+	if err != nil {
+		return "", fmt.Errorf("failed to get today's challenge: %w", err)
+	}
+
+	hints := b.problemHints(problem)
+
+	level, err := b.db.GetHintLevel(userID, problem.ID, date)
+	if err != nil {
+		return "", fmt.Errorf("failed to get hint level: %w", err)
+	}
+	if level >= len(hints) {
+		return fmt.Sprintf("🙈 That was the last hint!\n\n%s", hints[len(hints)-1]), nil
+	}
+
+	level++
+	if err := b.db.SetHintLevel(userID, problem.ID, date, level); err != nil {
+		return "", fmt.Errorf("failed to save hint level: %w", err)
+	}
+
+	text := fmt.Sprintf("💡 Hint %d/%d\n\n%s", level, len(hints), hints[level-1])
+	if b.config.HintPenalty > 0 {
+		text += fmt.Sprintf("\n\n⚖️ Each hint costs %g points when you solve it.", b.config.HintPenalty)
+	}
+	return text, nil
+}
+
+// problemHints lists the hints of a problem from the least to the most revealing:
+// its LeetCode topics, the hints added with /addhint, then the editorial. Problems
+// on other judges only get their category and the hints added with /addhint.
+func (b *Bot) problemHints(problem *models.Problem) []string {
+	var slug string
+	if problemPlatform(problem) == judge.LeetCode {
+		slug = leetcode.SlugFromURL(problem.URL)
+	}
+
+	topics := fmt.Sprintf("🏷️ Category: %s", chat.Escape(problem.Category))
+	if slug != "" {
+		if question, err := leetcode.GetQuestion(slug); err == nil && len(question.TopicTags) > 0 {
+			topics = fmt.Sprintf("🏷️ Topics: %s", chat.Escape(strings.Join(question.TopicTags, ", ")))
+		} else if err != nil {
+			log.Printf("Error getting metadata for %s: %v", problem.Title, err)
+		}
+	}
+	hints := []string{topics}
+
+	custom, err := b.db.GetProblemHints(problem.ID)
+	if err != nil {
+		log.Printf("Error getting hints for %s: %v", problem.Title, err)
+	}
+	for _, hint := range custom {
+		hints = append(hints, chat.Escape(hint))
+	}
+
+	if slug != "" {
+		hints = append(hints, "📖 Editorial: "+leetcode.SiteFromURL(problem.URL).EditorialURL(slug))
+	}
+
+	return hints
+}
+
+// fitsAlert reports whether a text can be shown in a callback alert
+func fitsAlert(text string) bool {
+	return utf8.RuneCountInString(text) <= maxAlertLength
+}
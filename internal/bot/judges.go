@@ -0,0 +1,186 @@
+package bot
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"leetcode-telegram-bot/internal/chat"
+	"leetcode-telegram-bot/internal/judge"
+	"leetcode-telegram-bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleLinkCommand handles the /link <platform> <handle> command for linking an account on
+// a judge other than LeetCode, which uses /register
+func (b *Bot) handleLinkCommand(message *tgbotapi.Message) {
+	args := strings.Fields(message.CommandArguments())
+	if len(args) != 2 {
+		b.sendMessage(message.Chat.ID, "❌ Usage: /link <platform> <handle>, e.g. /link codeforces tourist")
+		return
+	}
+
+	platform, err := judge.ParsePlatform(args[0])
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Unknown platform %s.", chat.Escape(args[0])))
+		return
+	}
+	if platform == judge.LeetCode {
+		b.sendMessage(message.Chat.ID, "❌ Use /register <leetcode_username> [com|cn] for LeetCode.")
+		return
+	}
+	checker, ok := b.judges[platform]
+	if !ok {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ I can't check solves on %s yet, tap \"I solved it\" instead.", platform.Name()))
+		return
+	}
+
+	// Make sure the handle exists before handing out a token
+	handle := strings.TrimPrefix(args[1], "@")
+	if _, err := checker.ProfileText(judge.Account{Handle: handle}); err != nil {
+		log.Printf("Error validating %s handle %s: %v", platform, handle, err)
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Could not find the user %s on %s. Please check the spelling and try again.", chat.Escape(handle), platform.Name()))
+		return
+	}
+
+	token, err := newVerificationToken()
+	if err != nil {
+		log.Printf("Error generating verification token: %v", err)
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ An error occurred while linking your %s account.", platform.Name()))
+		return
+	}
+	if err := b.db.LinkJudgeAccount(message.From.ID, string(platform), handle, token); err != nil {
+		log.Printf("Error linking %s account: %v", platform, err)
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ An error occurred while linking your %s account.", platform.Name()))
+		return
+	}
+
+	b.confirmInPrivate(message, fmt.Sprintf("✅ Linked your %s account: **%s**\n\n%s", platform.Name(), chat.Escape(handle),
+		judgeVerificationInstructions(platform, handle, token)),
+		fmt.Sprintf("✅ %s account linked, now it needs to be verified.", platform.Name()))
+}
+
+// handleVerifyJudgeAccount handles /verify <platform> for accounts linked with /link
+func (b *Bot) handleVerifyJudgeAccount(message *tgbotapi.Message, platform judge.Platform) {
+	checker, ok := b.judges[platform]
+	if !ok {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ I can't check solves on %s yet.", platform.Name()))
+		return
+	}
+
+	account, err := b.db.GetJudgeAccount(message.From.ID, string(platform))
+	if err == sql.ErrNoRows {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Please link your %s account first with /link %s <handle>.", platform.Name(), platform))
+		return
+	}
+	if err != nil {
+		log.Printf("Error getting %s account: %v", platform, err)
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ An error occurred while verifying your %s account.", platform.Name()))
+		return
+	}
+	if account.Verified {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Your %s account %s is already verified.", platform.Name(), chat.Escape(account.Handle)))
+		return
+	}
+
+	text, err := checker.ProfileText(judge.Account{Handle: account.Handle})
+	if err != nil {
+		log.Printf("Error getting %s profile of %s: %v", platform, account.Handle, err)
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Could not reach %s, please try again later.", platform.Name()))
+		return
+	}
+
+	if !strings.Contains(text, account.VerificationToken) {
+		b.confirmInPrivate(message, fmt.Sprintf("❌ I couldn't find the token on your %s profile yet.\n\n", platform.Name())+
+			judgeVerificationInstructions(platform, account.Handle, account.VerificationToken),
+			"❌ Not verified yet.")
+		return
+	}
+
+	if err := b.db.MarkJudgeAccountVerified(message.From.ID, string(platform)); err != nil {
+		log.Printf("Error verifying %s account: %v", platform, err)
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ An error occurred while verifying your %s account.", platform.Name()))
+		return
+	}
+
+	b.confirmInPrivate(message, fmt.Sprintf("🔓 Your %s account **%s** is verified! Your solves of %s problems will now be detected automatically. "+
+		"You can remove the token from your profile.", platform.Name(), chat.Escape(account.Handle), platform.Name()),
+		fmt.Sprintf("🔓 %s account verified.", platform.Name()))
+}
+
+// judgeVerificationInstructions explains how to verify an account on a judge with the given token
+func judgeVerificationInstructions(platform judge.Platform, handle, token string) string {
+	return fmt.Sprintf("🔐 To prove %s is yours, add this token to your %s profile's first name, last name or organization "+
+		"and then send /verify %s:\n\n`%s`\n\n"+
+		"Until then your solves are only counted as self-reported.", chat.Escape(handle), platform.Name(), platform, token)
+}
+
+// problemPlatform returns the judge of a problem. Problems read without their platform
+// column fall back to detecting it from the URL.
+func problemPlatform(problem *models.Problem) judge.Platform {
+	if problem.Platform != "" {
+		if platform, err := judge.ParsePlatform(problem.Platform); err == nil {
+			return platform
+		}
+	}
+	return judge.PlatformFromURL(problem.URL)
+}
+
+// verifiedAccount returns a user's verified account on a judge, or nil when they have none
+func (b *Bot) verifiedAccount(userID int64, platform judge.Platform) (*judge.Account, error) {
+	if platform == judge.LeetCode {
+		link, err := b.db.GetLeetcodeLink(userID)
+		if err == sql.ErrNoRows || (err == nil && !link.Verified) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &judge.Account{Handle: link.LeetCodeUsername, Site: link.Site}, nil
+	}
+
+	account, err := b.db.GetJudgeAccount(userID, string(platform))
+	if err == sql.ErrNoRows || (err == nil && !account.Verified) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &judge.Account{Handle: account.Handle}, nil
+}
+
+// acceptedSolve looks up the user's accepted submission of a problem on the given date on
+// its judge. It returns nil when the judge has no checker, the user has no verified account
+// there, or no such submission was found.
+func (b *Bot) acceptedSolve(userID int64, problem *models.Problem, date string) (*time.Time, error) {
+	platform := problemPlatform(problem)
+	checker, ok := b.judges[platform]
+	if !ok {
+		return nil, nil
+	}
+
+	// Only accounts proven to belong to the user can credit solves
+	account, err := b.verifiedAccount(userID, platform)
+	if err != nil || account == nil {
+		return nil, err
+	}
+
+	recent, err := checker.RecentAccepted(*account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent accepted submissions of %s: %w", account.Handle, err)
+	}
+
+	problemID := checker.ProblemID(problem.URL)
+	if problemID == "" {
+		return nil, nil
+	}
+	for _, ac := range recent {
+		if ac.ProblemID == problemID && ac.At.In(b.location).Format("2006-01-02") == date {
+			return &ac.At, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,130 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"leetcode-telegram-bot/internal/chat"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// settingLanguage is the group setting key of the message language
+const settingLanguage = "language"
+
+// platformSetting returns the key a setting of a platform's group is stored under. Settings
+// of the Slack and Discord channels are stored on the main group too, with the platform appended.
+func platformSetting(key, platform string) string {
+	if platform == chat.PlatformTelegram {
+		return key
+	}
+	return key + "_" + platform
+}
+
+// language returns the language of the messages for a platform's group
+func (b *Bot) language(platform string) string {
+	language, err := b.db.GetGroupSetting(b.config.TelegramGroupID, platformSetting(settingLanguage, platform), b.config.DefaultLanguage)
+	if err != nil {
+		log.Printf("Error getting language setting: %v", err)
+	}
+	if !b.messages.HasLanguage(language) {
+		return b.config.DefaultLanguage
+	}
+	return language
+}
+
+// handleLanguageCommand handles the /language command for showing or changing the
+// language of a group's messages
+func (b *Bot) handleLanguageCommand(message *tgbotapi.Message) {
+	if !b.isGroupAdminMessage(message) {
+		return
+	}
+
+	usage := fmt.Sprintf("❌ Usage: /language [telegram|slack|discord] <%s>", strings.Join(b.messages.Languages(), "|"))
+	args := strings.Fields(strings.ToLower(message.CommandArguments()))
+
+	if len(args) == 0 {
+		var responseText strings.Builder
+		responseText.WriteString("🌐 **Languages**\n\n")
+		for _, group := range b.groups {
+			responseText.WriteString(fmt.Sprintf("• %s: %s\n", group.Platform.Name(), b.language(group.Platform.Name())))
+		}
+		responseText.WriteString(fmt.Sprintf("\nAvailable: %s", strings.Join(b.messages.Languages(), ", ")))
+		b.sendMessage(message.Chat.ID, responseText.String())
+		return
+	}
+
+	platform := chat.PlatformTelegram
+	if len(args) == 2 {
+		platform = args[0]
+		args = args[1:]
+	}
+	if len(args) != 1 {
+		b.sendMessage(message.Chat.ID, usage)
+		return
+	}
+
+	found := false
+	for _, group := range b.groups {
+		if group.Platform.Name() == platform {
+			found = true
+		}
+	}
+	if !found {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ The bot doesn't run on %s.", chat.Escape(platform)))
+		return
+	}
+	if !b.messages.HasLanguage(args[0]) {
+		b.sendMessage(message.Chat.ID, usage)
+		return
+	}
+
+	if err := b.db.SetGroupSetting(b.config.TelegramGroupID, platformSetting(settingLanguage, platform), args[0]); err != nil {
+		log.Printf("Error saving language setting: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while saving the setting.")
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Messages on %s are now in %s.", platform, args[0]))
+}
+
+// handleTemplatesCommand handles the /templates command for showing the overridden message
+// templates and loading them again after they were edited
+func (b *Bot) handleTemplatesCommand(message *tgbotapi.Message) {
+	if !b.isGroupAdminMessage(message) {
+		return
+	}
+
+	args := strings.Fields(strings.ToLower(message.CommandArguments()))
+	if len(args) > 0 && args[0] != "reload" {
+		b.sendMessage(message.Chat.ID, "❌ Usage: /templates [reload]")
+		return
+	}
+
+	if b.config.TemplatesDir == "" {
+		b.sendMessage(message.Chat.ID, "📄 Only the built-in message templates are used. Set `TEMPLATES_DIR` to override them.")
+		return
+	}
+
+	if len(args) > 0 {
+		if err := b.messages.Reload(); err != nil {
+			log.Printf("Error reloading message templates: %v", err)
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error loading templates, the previous ones are kept: %v", err))
+			return
+		}
+	}
+
+	overridden := b.messages.Overridden()
+	if len(overridden) == 0 {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("📄 No templates in `%s`, the built-in ones are used.", b.config.TemplatesDir))
+		return
+	}
+
+	var responseText strings.Builder
+	responseText.WriteString(fmt.Sprintf("📄 **Templates from** `%s`\n\n", b.config.TemplatesDir))
+	for _, name := range overridden {
+		responseText.WriteString(fmt.Sprintf("• `%s`\n", name))
+	}
+	b.sendMessage(message.Chat.ID, responseText.String())
+}
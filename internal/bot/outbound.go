@@ -0,0 +1,238 @@
+package bot
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"leetcode-telegram-bot/internal/events"
+	"leetcode-telegram-bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleWebhookCommand handles the /webhook command and its add, list and remove subcommands
+// for outbound webhooks that receive challenge events. Only admins of the main group may use
+// it, and URLs are only shown in private chat since Slack and Discord URLs are credentials.
+func (b *Bot) handleWebhookCommand(message *tgbotapi.Message) {
+	if !message.Chat.IsPrivate() && !b.isMainGroupMessage(message) {
+		return
+	}
+	if !b.isGroupAdmin(message) {
+		b.sendMessage(message.Chat.ID, "❌ Only admins of the main group can manage webhooks.")
+		return
+	}
+
+	usage := "❌ Usage: /webhook add <url> [events], /webhook list or /webhook remove <id>"
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 {
+		b.sendMessage(message.Chat.ID, usage)
+		return
+	}
+
+	subcommand := strings.ToLower(args[0])
+	if (subcommand == "add" || subcommand == "list") && !message.Chat.IsPrivate() {
+		b.sendMessage(message.Chat.ID, "❌ Webhook URLs can hold credentials, please use /webhook add and /webhook list in a private chat with me.")
+		return
+	}
+
+	switch subcommand {
+	case "add":
+		if len(args) < 2 || len(args) > 3 {
+			b.sendMessage(message.Chat.ID, "❌ Usage: /webhook add <url> [events]\n\nEvents: "+strings.Join(events.All, ", ")+
+				" separated by commas, all of them by default.")
+			return
+		}
+		eventList := events.Wildcard
+		if len(args) == 3 {
+			eventList = args[2]
+		}
+		b.handleWebhookAdd(message, args[1], eventList)
+	case "list":
+		b.handleWebhookList(message)
+	case "remove":
+		if len(args) != 2 {
+			b.sendMessage(message.Chat.ID, "❌ Usage: /webhook remove <id>")
+			return
+		}
+		b.handleWebhookRemove(message, args[1])
+	default:
+		b.sendMessage(message.Chat.ID, usage)
+	}
+}
+
+// handleWebhookAdd registers a webhook and sends its signing secret. It is only used in private chat.
+func (b *Bot) handleWebhookAdd(message *tgbotapi.Message, rawURL, eventList string) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		b.sendMessage(message.Chat.ID, "❌ Please provide a valid http(s) URL.")
+		return
+	}
+
+	subscribed := strings.Split(eventList, ",")
+	for _, event := range subscribed {
+		if !events.Valid(event) {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Unknown event %s. Events: %s", event, strings.Join(events.All, ", ")))
+			return
+		}
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		log.Printf("Error generating webhook secret: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while adding the webhook.")
+		return
+	}
+
+	webhook := &models.OutboundWebhook{URL: rawURL, Secret: secret, Events: subscribed}
+	id, err := b.db.AddOutboundWebhook(webhook)
+	if err != nil {
+		log.Printf("Error adding webhook: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while adding the webhook.")
+		return
+	}
+
+	// A webhook nobody knows the secret of is useless, so it is only kept if the secret was sent
+	details := fmt.Sprintf("✅ Webhook #%d added for %s\n\nDeliveries carry a %s header with the HMAC-SHA256 of the body, "+
+		"keyed with this secret:\n\n`%s`", id, strings.Join(subscribed, ", "), events.SignatureHeader, secret)
+	if _, err := b.postMessage(message.Chat.ID, details, nil); err != nil {
+		log.Printf("Error sending webhook secret to user %d: %v", message.From.ID, err)
+		if _, err := b.db.RemoveOutboundWebhook(id); err != nil {
+			log.Printf("Error removing webhook %d: %v", id, err)
+		}
+		b.sendMessage(message.Chat.ID, "❌ I couldn't send you the webhook's secret, please try again.")
+	}
+}
+
+// handleWebhookList shows the registered webhooks with their delivery counts
+func (b *Bot) handleWebhookList(message *tgbotapi.Message) {
+	webhooks, err := b.db.GetOutboundWebhooks()
+	if err != nil {
+		log.Printf("Error getting webhooks: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while fetching the webhooks.")
+		return
+	}
+
+	if len(webhooks) == 0 {
+		b.sendMessage(message.Chat.ID, "📭 No webhooks registered. Add one with /webhook add <url> [events].")
+		return
+	}
+
+	var responseText strings.Builder
+	responseText.WriteString("🔗 **Webhooks** 🔗\n\n")
+
+	for _, webhook := range webhooks {
+		counts, err := b.db.CountWebhookDeliveries(webhook.ID)
+		if err != nil {
+			log.Printf("Error counting deliveries of webhook %d: %v", webhook.ID, err)
+		}
+		responseText.WriteString(fmt.Sprintf("#%d %s\n   %s - %d delivered, %d pending, %d failed\n",
+			webhook.ID, webhook.URL, strings.Join(webhook.Events, ", "),
+			counts["delivered"], counts["pending"], counts["failed"]))
+	}
+
+	b.sendMessage(message.Chat.ID, responseText.String())
+}
+
+// handleWebhookRemove removes a webhook along with its undelivered events
+func (b *Bot) handleWebhookRemove(message *tgbotapi.Message, arg string) {
+	id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Usage: /webhook remove <id>")
+		return
+	}
+
+	removed, err := b.db.RemoveOutboundWebhook(id)
+	if err != nil {
+		log.Printf("Error removing webhook %d: %v", id, err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while removing the webhook.")
+		return
+	}
+	if !removed {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Webhook #%d was not found.", id))
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("🗑️ Webhook #%d removed.", id))
+}
+
+// newWebhookSecret generates a random key for signing webhook deliveries
+func newWebhookSecret() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// DeliverWebhooks sends queued events to the outbound webhooks
+func (b *Bot) DeliverWebhooks() error {
+	return b.events.Deliver()
+}
+
+// PublishBrokenStreaks sends a streak.broken event for every member whose streak ends with
+// today's challenge. Run it at the end of the day, once the challenge can't be solved anymore.
+func (b *Bot) PublishBrokenStreaks() error {
+	if !b.events.Subscribed(events.StreakBroken) {
+		return nil
+	}
+
+	today := b.today()
+	if _, err := b.db.GetDailyChallenge(today); err != nil {
+		log.Printf("No challenge today, no streaks to break")
+		return nil
+	}
+
+	users, err := b.db.GetUsersWhoDidntSubmitToday(today)
+	if err != nil {
+		return fmt.Errorf("failed to get users who didn't submit: %w", err)
+	}
+
+	for _, user := range users {
+		// Today's open challenge isn't counted yet, so this is the streak that ends today
+		stats, err := b.db.GetUserStats(user.ID, today)
+		if err != nil {
+			log.Printf("Error getting stats of user %d: %v", user.ID, err)
+			continue
+		}
+		if stats.CurrentStreak == 0 {
+			continue
+		}
+
+		b.events.Publish(events.StreakBroken,
+			fmt.Sprintf("💔 %s's %d-day streak ended", user.FirstName, stats.CurrentStreak),
+			events.StreakBrokenData{Date: today, User: &stats.User, Streak: stats.CurrentStreak})
+	}
+
+	return nil
+}
+
+// publishSubmission sends a submission.recorded event for a stored submission
+func (b *Bot) publishSubmission(submission *models.Submission) {
+	if !b.events.Subscribed(events.SubmissionRecorded) {
+		return
+	}
+
+	user, err := b.db.GetUser(submission.UserID)
+	if err != nil {
+		log.Printf("Error getting user %d: %v", submission.UserID, err)
+		return
+	}
+	problem, dayNumber, err := b.db.GetTodaysChallengeWithDay(submission.Date)
+	if err != nil {
+		log.Printf("Error getting the challenge of %s: %v", submission.Date, err)
+		return
+	}
+
+	how := "self-reported"
+	if submission.Verified {
+		how = "verified"
+	}
+	b.events.Publish(events.SubmissionRecorded,
+		fmt.Sprintf("✅ %s solved Day %d: %s (%s)", user.FirstName, dayNumber, problem.Title, how),
+		events.SubmissionRecordedData{DayNumber: dayNumber, User: user, Problem: problem, Submission: submission})
+}
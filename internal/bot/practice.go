@@ -0,0 +1,272 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"leetcode-telegram-bot/internal/chat"
+	"leetcode-telegram-bot/internal/database"
+	"leetcode-telegram-bot/internal/leetcode"
+	"leetcode-telegram-bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// difficulties lists the LeetCode difficulty levels accepted by /practice
+var difficulties = []string{"Easy", "Medium", "Hard"}
+
+// handlePracticeCommand handles the /practice command in private chats.
+// Practice solves are tracked separately and never touch the group leaderboard.
+func (b *Bot) handlePracticeCommand(message *tgbotapi.Message) {
+	if !message.Chat.IsPrivate() {
+		b.sendMessage(message.Chat.ID, "🔒 Practice mode works in private chat. Send me /practice in a DM!")
+		return
+	}
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 1 {
+		switch strings.ToLower(args[0]) {
+		case "stats":
+			b.handlePracticeStats(message)
+			return
+		case "check":
+			b.handlePracticeCheck(message)
+			return
+		}
+	}
+
+	link, err := b.db.GetLeetcodeLink(message.From.ID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Please register your LeetCode username first with /register <leetcode_username>.")
+		return
+	}
+	if !link.Verified {
+		b.sendMessage(message.Chat.ID, "❌ Practice problems are checked on your LeetCode account, please /verify it first.")
+		return
+	}
+
+	// The last argument may be a difficulty, everything else is the category
+	var difficulty string
+	if len(args) > 0 {
+		for _, d := range difficulties {
+			if strings.EqualFold(args[len(args)-1], d) {
+				difficulty = d
+				args = args[:len(args)-1]
+				break
+			}
+		}
+	}
+	category := strings.Join(args, " ")
+
+	// Settle open practice problems first so the recent solves are up to date. LeetCode only
+	// shares the last 15 accepted submissions, so older solves can't be told apart.
+	recent, err := leetcode.GetRecentAC(leetcode.Site(link.Site), link.LeetCodeUsername)
+	if err != nil {
+		log.Printf("Error getting recent AC submissions for %s: %v", link.LeetCodeUsername, err)
+	}
+	b.settlePracticeProblems(message.From.ID, recent)
+
+	solvedSlugs := make(map[string]bool)
+	for _, ac := range recent {
+		solvedSlugs[ac.TitleSlug] = true
+	}
+
+	candidates, err := b.db.GetPracticeCandidates(message.From.ID, category, difficulty, 50)
+	if err != nil {
+		log.Printf("Error getting practice candidates: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while picking a practice problem.")
+		return
+	}
+
+	var problem *models.Problem
+	for i := range candidates {
+		if !solvedSlugs[leetcode.SlugFromURL(candidates[i].URL)] {
+			problem = &candidates[i]
+			break
+		}
+	}
+	if problem == nil {
+		b.sendMessage(message.Chat.ID, "🤷 No unsolved problems match that filter. Try another category or difficulty.")
+		return
+	}
+
+	if err := b.db.AddPracticeProblem(message.From.ID, problem.ID); err != nil {
+		log.Printf("Error adding practice problem: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while saving your practice problem.")
+		return
+	}
+
+	difficultyText := problem.Difficulty
+	if difficultyText == "" || difficultyText == database.UnknownDifficulty {
+		difficultyText = "Unknown"
+	}
+	responseText := fmt.Sprintf("🏋️ **Practice Problem**\n\n"+
+		"📝 **%s**\n"+
+		"🏷️ Category: %s\n"+
+		"📶 Difficulty: %s\n"+
+		"🔗 %s\n\n"+
+		"I'll spot your accepted submission on LeetCode automatically, or use /practice check.",
+		chat.Escape(problem.Title), chat.Escape(problem.Category), difficultyText, leetcode.Site(link.Site).ProblemURL(leetcode.SlugFromURL(problem.URL)))
+
+	b.sendMessage(message.Chat.ID, responseText)
+}
+
+// handlePracticeCheck checks the user's open practice problems right away
+func (b *Bot) handlePracticeCheck(message *tgbotapi.Message) {
+	link, err := b.db.GetLeetcodeLink(message.From.ID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Please register your LeetCode username first with /register <leetcode_username>.")
+		return
+	}
+	if !link.Verified {
+		b.sendMessage(message.Chat.ID, "❌ Practice problems are checked on your LeetCode account, please /verify it first.")
+		return
+	}
+
+	recent, err := leetcode.GetRecentAC(leetcode.Site(link.Site), link.LeetCodeUsername)
+	if err != nil {
+		log.Printf("Error getting recent AC submissions for %s: %v", link.LeetCodeUsername, err)
+		b.sendMessage(message.Chat.ID, "❌ Could not reach LeetCode, please try again later.")
+		return
+	}
+
+	if solved := b.settlePracticeProblems(message.From.ID, recent); solved == 0 {
+		b.sendMessage(message.Chat.ID, "🔍 No new practice solves found yet. Keep going! 💪")
+	}
+}
+
+// handlePracticeStats shows the user's personal practice statistics
+func (b *Bot) handlePracticeStats(message *tgbotapi.Message) {
+	stats, err := b.db.GetPracticeStats(message.From.ID)
+	if err != nil {
+		log.Printf("Error getting practice stats: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while fetching your practice stats.")
+		return
+	}
+
+	if stats.Assigned == 0 {
+		b.sendMessage(message.Chat.ID, "📊 No practice yet! Use /practice [category] [difficulty] to get a problem.")
+		return
+	}
+
+	var responseText strings.Builder
+	responseText.WriteString("📊 **Your Practice Stats** 📊\n\n")
+	responseText.WriteString(fmt.Sprintf("✅ Solved: %d of %d handed out\n", stats.Solved, stats.Assigned))
+
+	if stats.Solved > 0 {
+		responseText.WriteString("\n📶 **By difficulty:**\n")
+		for _, d := range append(difficulties, "Unknown") {
+			if count := stats.ByDifficulty[d]; count > 0 {
+				responseText.WriteString(fmt.Sprintf("• %s: %d\n", d, count))
+			}
+		}
+
+		categories := make([]string, 0, len(stats.ByCategory))
+		for category := range stats.ByCategory {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+
+		responseText.WriteString("\n🏷️ **By category:**\n")
+		for _, category := range categories {
+			responseText.WriteString(fmt.Sprintf("• %s: %d\n", category, stats.ByCategory[category]))
+		}
+	}
+
+	b.sendMessage(message.Chat.ID, responseText.String())
+}
+
+// settlePracticeProblems marks open practice problems found in the recent accepted
+// submissions as solved and congratulates the user in DM. It returns how many were solved.
+func (b *Bot) settlePracticeProblems(userID int64, recent []leetcode.RecentAC) int {
+	open, err := b.db.GetOpenPracticeProblems(userID)
+	if err != nil {
+		log.Printf("Error getting open practice problems for user %d: %v", userID, err)
+		return 0
+	}
+
+	solved := 0
+	for _, problem := range open {
+		slug := leetcode.SlugFromURL(problem.URL)
+		for _, ac := range recent {
+			if ac.TitleSlug != slug || ac.Timestamp.Before(problem.AssignedAt) {
+				continue
+			}
+			if err := b.db.MarkPracticeSolved(problem.ID, ac.Timestamp); err != nil {
+				log.Printf("Error marking practice problem %d as solved: %v", problem.ID, err)
+				break
+			}
+			solved++
+			b.sendMessage(userID, fmt.Sprintf("🎯 Practice solved: **%s**! Use /practice for another one.", chat.Escape(problem.Title)))
+			break
+		}
+	}
+
+	return solved
+}
+
+// CheckPracticeSubmissions checks open practice problems of all users against their recent LeetCode solves
+func (b *Bot) CheckPracticeSubmissions() error {
+	userIDs, err := b.db.GetUsersWithOpenPractice()
+	if err != nil {
+		return fmt.Errorf("failed to get users with open practice problems: %w", err)
+	}
+
+	for _, userID := range userIDs {
+		link, err := b.db.GetLeetcodeLink(userID)
+		if err != nil {
+			log.Printf("Error getting LeetCode profile for user %d: %v", userID, err)
+			continue
+		}
+		// Only a verified account proves the solves are the user's own
+		if !link.Verified {
+			continue
+		}
+
+		recent, err := leetcode.GetRecentAC(leetcode.Site(link.Site), link.LeetCodeUsername)
+		if err != nil {
+			log.Printf("Error getting recent AC submissions for %s: %v", link.LeetCodeUsername, err)
+			continue
+		}
+
+		b.settlePracticeProblems(userID, recent)
+	}
+
+	return nil
+}
+
+// SyncProblemDifficulties fills in missing problem difficulties from LeetCode metadata
+func (b *Bot) SyncProblemDifficulties(limit int) error {
+	problems, err := b.db.GetProblemsMissingDifficulty(limit)
+	if err != nil {
+		return fmt.Errorf("failed to get problems missing difficulty: %w", err)
+	}
+
+	for _, problem := range problems {
+		slug := leetcode.SlugFromURL(problem.URL)
+		var question *leetcode.Question
+		if slug != "" {
+			question, err = leetcode.GetQuestion(slug)
+		}
+		// Problems LeetCode will never know are marked, so they don't hold up the next batches
+		if slug == "" || errors.Is(err, leetcode.ErrQuestionNotFound) {
+			log.Printf("No LeetCode metadata for %s, marking its difficulty unknown", problem.Title)
+			if err := b.db.SetProblemDifficulty(problem.ID, database.UnknownDifficulty); err != nil {
+				log.Printf("Error saving difficulty of %s: %v", problem.Title, err)
+			}
+			continue
+		}
+		if err != nil {
+			log.Printf("Error getting metadata for %s: %v", problem.Title, err)
+			continue
+		}
+		if err := b.db.SetProblemDifficulty(problem.ID, question.Difficulty); err != nil {
+			log.Printf("Error saving difficulty of %s: %v", problem.Title, err)
+		}
+	}
+
+	return nil
+}
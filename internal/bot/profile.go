@@ -0,0 +1,183 @@
+package bot
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"leetcode-telegram-bot/internal/chat"
+	"leetcode-telegram-bot/internal/leetcode"
+	"leetcode-telegram-bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleProfileCommand handles the /profile [@user] command showing synced LeetCode stats,
+// and /profile top showing who improved most this month
+func (b *Bot) handleProfileCommand(message *tgbotapi.Message) {
+	arg := strings.TrimSpace(message.CommandArguments())
+	if strings.EqualFold(arg, "top") {
+		b.handleProfileTop(message)
+		return
+	}
+
+	user := &models.User{ID: message.From.ID, FirstName: message.From.FirstName}
+	if arg != "" {
+		found, err := b.db.GetUserByUsername(strings.TrimPrefix(arg, "@"))
+		if err == sql.ErrNoRows {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ I don't know %s yet.", arg))
+			return
+		}
+		if err != nil {
+			log.Printf("Error getting user %s: %v", arg, err)
+			b.sendMessage(message.Chat.ID, "❌ An error occurred while looking up the user.")
+			return
+		}
+		user = found
+	}
+
+	link, err := b.db.GetLeetcodeLink(user.ID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ %s hasn't registered a LeetCode username yet. Use /register <leetcode_username>.", chat.Escape(user.FirstName)))
+		return
+	}
+
+	monthAgo := time.Now().AddDate(0, -1, 0)
+	history, err := b.db.GetProfileHistory(user.ID, monthAgo)
+	if err != nil {
+		log.Printf("Error getting profile history: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while fetching the profile.")
+		return
+	}
+	if len(history) == 0 {
+		// Not synced yet, take the first snapshot now
+		if err := b.syncProfile(user.ID, leetcode.Site(link.Site), link.LeetCodeUsername); err != nil {
+			log.Printf("Error syncing LeetCode profile of %s: %v", link.LeetCodeUsername, err)
+			b.sendMessage(message.Chat.ID, "❌ Could not reach LeetCode, please try again later.")
+			return
+		}
+		history, err = b.db.GetProfileHistory(user.ID, monthAgo)
+		if err != nil || len(history) == 0 {
+			log.Printf("Error getting profile history: %v", err)
+			b.sendMessage(message.Chat.ID, "❌ An error occurred while fetching the profile.")
+			return
+		}
+	}
+
+	b.sendMessage(message.Chat.ID, formatProfile(user.FirstName, history))
+}
+
+// handleProfileTop shows who solved the most problems on LeetCode since the start of the month
+func (b *Bot) handleProfileTop(message *tgbotapi.Message) {
+	now := b.now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, b.location)
+
+	improvements, err := b.db.GetProfileImprovements(monthStart, 10)
+	if err != nil {
+		log.Printf("Error getting profile improvements: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while fetching the profiles.")
+		return
+	}
+
+	if len(improvements) == 0 {
+		b.sendMessage(message.Chat.ID, "📈 Not enough LeetCode history this month yet. Profiles are synced daily.")
+		return
+	}
+
+	var responseText strings.Builder
+	responseText.WriteString(fmt.Sprintf("📈 **Most Improved in %s** 📈\n\n", now.Format("January")))
+	for i, improvement := range improvements {
+		responseText.WriteString(fmt.Sprintf("%s %s (%s) - +%d solved", rankEmoji(i), chat.Escape(improvement.FirstName), chat.Escape(improvement.LeetCodeUsername), improvement.SolvedGain))
+		if improvement.RatingGain != 0 {
+			responseText.WriteString(fmt.Sprintf(", rating %+.0f", improvement.RatingGain))
+		}
+		responseText.WriteString("\n")
+	}
+
+	b.sendMessage(message.Chat.ID, responseText.String())
+}
+
+// SyncLeetcodeProfiles stores a snapshot of the public LeetCode stats of every registered user
+func (b *Bot) SyncLeetcodeProfiles() error {
+	profiles, err := b.db.GetLeetcodeProfiles()
+	if err != nil {
+		return fmt.Errorf("failed to get LeetCode profiles: %w", err)
+	}
+
+	synced := 0
+	for _, profile := range profiles {
+		if err := b.syncProfile(profile.UserId, leetcode.Site(profile.Site), profile.LeetCodeUsername); err != nil {
+			log.Printf("Error syncing LeetCode profile of %s: %v", profile.LeetCodeUsername, err)
+			continue
+		}
+		synced++
+	}
+
+	log.Printf("Synced %d of %d LeetCode profiles", synced, len(profiles))
+	return nil
+}
+
+// syncProfile fetches a user's public LeetCode stats from their site and stores them as a snapshot
+func (b *Bot) syncProfile(userID int64, site leetcode.Site, username string) error {
+	stats, err := leetcode.GetUserProfile(site, username)
+	if err != nil {
+		return err
+	}
+
+	return b.db.AddProfileSnapshot(&models.ProfileSnapshot{
+		UserID:           userID,
+		LeetCodeUsername: username,
+		EasySolved:       stats.EasySolved,
+		MediumSolved:     stats.MediumSolved,
+		HardSolved:       stats.HardSolved,
+		TotalSolved:      stats.TotalSolved,
+		Ranking:          stats.Ranking,
+		ContestRating:    stats.ContestRating,
+		ContestsAttended: stats.ContestsAttended,
+		Badges:           stats.Badges,
+	})
+}
+
+// formatProfile formats the latest LeetCode stats of a user with their progress over
+// the last week and month. The history must be ordered oldest first.
+func formatProfile(firstName string, history []models.ProfileSnapshot) string {
+	latest := history[len(history)-1]
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("👤 **%s on LeetCode** (%s)\n\n", chat.Escape(firstName), chat.Escape(latest.LeetCodeUsername)))
+	text.WriteString(fmt.Sprintf("✅ Solved: %d (🟢 %d / 🟡 %d / 🔴 %d)\n", latest.TotalSolved, latest.EasySolved, latest.MediumSolved, latest.HardSolved))
+	if latest.Ranking > 0 {
+		text.WriteString(fmt.Sprintf("🌍 Ranking: #%d\n", latest.Ranking))
+	}
+	if latest.ContestsAttended > 0 {
+		text.WriteString(fmt.Sprintf("🏆 Contest rating: %.0f (%d contests)\n", latest.ContestRating, latest.ContestsAttended))
+	}
+	if len(latest.Badges) > 0 {
+		text.WriteString(fmt.Sprintf("🎖️ Badges: %s\n", strings.Join(latest.Badges, ", ")))
+	}
+
+	weekAgo := time.Now().AddDate(0, 0, -7)
+	weekStart := latest
+	for _, snapshot := range history {
+		if !snapshot.CreatedAt.Before(weekAgo) {
+			weekStart = snapshot
+			break
+		}
+	}
+	monthStart := history[0]
+
+	if len(history) > 1 {
+		text.WriteString("\n📅 **Progress:**\n")
+		text.WriteString(fmt.Sprintf("• Last 7 days: +%d solved\n", latest.TotalSolved-weekStart.TotalSolved))
+		text.WriteString(fmt.Sprintf("• Last 30 days: +%d solved", latest.TotalSolved-monthStart.TotalSolved))
+		if rating := latest.ContestRating - monthStart.ContestRating; rating != 0 && monthStart.ContestsAttended > 0 {
+			text.WriteString(fmt.Sprintf(", rating %+.0f", rating))
+		}
+		text.WriteString("\n")
+	}
+
+	text.WriteString(fmt.Sprintf("\n🔄 Last synced %s", latest.CreatedAt.Format("Jan 2 15:04 UTC")))
+	return text.String()
+}
@@ -0,0 +1,160 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"leetcode-telegram-bot/internal/chat"
+	"leetcode-telegram-bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// refreshProgressBoard edits the pinned progress board of the given date to list the
+// verified solves so far, posting and pinning the board first if it doesn't exist yet.
+// The chats on other platforms get the same board, without the pin. Finalized boards are
+// left untouched.
+func (b *Bot) refreshProgressBoard(date string) error {
+	challenge, err := b.db.GetDailyChallenge(date)
+	if err != nil {
+		return fmt.Errorf("failed to get daily challenge: %w", err)
+	}
+	if challenge.BoardFinalized {
+		return nil
+	}
+
+	solves, err := b.db.GetVerifiedSolves(date)
+	if err != nil {
+		return fmt.Errorf("failed to get verified solves: %w", err)
+	}
+
+	text := b.formatProgressBoard(challenge, solves)
+	b.refreshGroupMessages(date, boardKind, func(string) string { return text })
+
+	if challenge.BoardMessageID != 0 {
+		err := b.editMessage(b.config.TelegramGroupID, challenge.BoardMessageID, text, nil)
+		if err == nil {
+			return nil
+		}
+		log.Printf("Error editing progress board, posting a new one: %v", err)
+	}
+
+	messageID, err := b.postMessage(b.config.TelegramGroupID, text, nil)
+	if err != nil {
+		return fmt.Errorf("failed to send progress board: %w", err)
+	}
+	if err := b.db.SetBoardMessageID(date, messageID); err != nil {
+		return fmt.Errorf("failed to save progress board message ID: %w", err)
+	}
+
+	pin := tgbotapi.PinChatMessageConfig{
+		ChatID:              b.config.TelegramGroupID,
+		MessageID:           messageID,
+		DisableNotification: true,
+	}
+	if _, err := b.api.Request(pin); err != nil {
+		log.Printf("Error pinning progress board (is the bot an admin?): %v", err)
+	}
+
+	return nil
+}
+
+// FinalizeProgressBoard closes today's progress board with an end-of-day summary and unpins it
+func (b *Bot) FinalizeProgressBoard() error {
+	today := b.today()
+
+	challenge, err := b.db.GetDailyChallenge(today)
+	if err != nil {
+		log.Printf("No challenge today, nothing to finalize")
+		return nil
+	}
+	if challenge.BoardFinalized || challenge.BoardMessageID == 0 {
+		return nil
+	}
+
+	solves, err := b.db.GetVerifiedSolves(today)
+	if err != nil {
+		return fmt.Errorf("failed to get verified solves: %w", err)
+	}
+	members, err := b.db.CountUsers()
+	if err != nil {
+		return fmt.Errorf("failed to count users: %w", err)
+	}
+
+	var summary strings.Builder
+	summary.WriteString(b.formatProgressBoard(challenge, solves))
+	summary.WriteString("\n\n🏁 **Final result**\n")
+	if len(solves) == 0 {
+		summary.WriteString("Nobody solved it today. Tomorrow is a new day! 💪")
+	} else {
+		summary.WriteString(fmt.Sprintf("%d of %d members solved it", len(solves), members))
+		if members > 0 {
+			summary.WriteString(fmt.Sprintf(" (%d%%)", len(solves)*100/members))
+		}
+		summary.WriteString(fmt.Sprintf("\n🥇 First solver: %s", chat.Escape(solves[0].FirstName)))
+		if fastest := fastestSolve(solves); fastest != nil {
+			summary.WriteString(fmt.Sprintf("\n⚡ Fastest solver: %s in %s", chat.Escape(fastest.FirstName),
+				formatSolveTime(time.Duration(*fastest.SolveSeconds)*time.Second)))
+		}
+	}
+
+	b.editGroupMessages(today, boardKind, summary.String())
+	if err := b.editMessage(b.config.TelegramGroupID, challenge.BoardMessageID, summary.String(), nil); err != nil {
+		return fmt.Errorf("failed to finalize progress board: %w", err)
+	}
+	if err := b.db.MarkBoardFinalized(today); err != nil {
+		return fmt.Errorf("failed to mark progress board as finalized: %w", err)
+	}
+
+	unpin := tgbotapi.UnpinChatMessageConfig{
+		ChatID:    b.config.TelegramGroupID,
+		MessageID: challenge.BoardMessageID,
+	}
+	if _, err := b.api.Request(unpin); err != nil {
+		log.Printf("Error unpinning progress board: %v", err)
+	}
+
+	log.Printf("Finalized progress board for Day %d with %d solves", challenge.DayNumber, len(solves))
+	return nil
+}
+
+// formatProgressBoard builds the progress board text listing verified solves in order
+func (b *Bot) formatProgressBoard(challenge *models.DailyChallenge, solves []models.SolveEntry) string {
+	var board strings.Builder
+	board.WriteString(fmt.Sprintf("📋 **Day %d Progress** 📋\n\n", challenge.DayNumber))
+
+	if len(solves) == 0 {
+		board.WriteString("No verified solves yet. Who's going to be first? 👀")
+		return board.String()
+	}
+
+	for i, solve := range solves {
+		name := chat.Escape(solve.FirstName)
+		if solve.LastName != "" {
+			name += " " + chat.Escape(solve.LastName)
+		}
+		board.WriteString(fmt.Sprintf("%d. %s - %s", i+1, name, solve.SolvedAt.In(b.location).Format("15:04")))
+		if solve.SolveSeconds != nil {
+			board.WriteString(fmt.Sprintf(" (%s)", formatSolveTime(time.Duration(*solve.SolveSeconds)*time.Second)))
+		}
+		board.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(board.String(), "\n")
+}
+
+// fastestSolve finds the solve with the shortest known time to solve, or nil if none is known
+func fastestSolve(solves []models.SolveEntry) *models.SolveEntry {
+	var fastest *models.SolveEntry
+	for i := range solves {
+		if solves[i].SolveSeconds == nil {
+			continue
+		}
+		if fastest == nil || *solves[i].SolveSeconds < *fastest.SolveSeconds {
+			fastest = &solves[i]
+		}
+	}
+	return fastest
+}
@@ -0,0 +1,198 @@
+package bot
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"leetcode-telegram-bot/internal/chat"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleQueueCommand handles the /queue command and its add, list and remove subcommands
+func (b *Bot) handleQueueCommand(message *tgbotapi.Message) {
+	if !b.isGroupAdminMessage(message) {
+		return
+	}
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 {
+		b.sendMessage(message.Chat.ID, "❌ Usage: /queue add <slug> [YYYY-MM-DD], /queue list or /queue remove <id|slug>")
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "add":
+		b.handleQueueAdd(message, args[1:])
+	case "list":
+		b.handleQueueList(message)
+	case "remove":
+		b.handleQueueRemove(message, args[1:])
+	default:
+		b.sendMessage(message.Chat.ID, "❌ Unknown subcommand. Usage: /queue add <slug> [YYYY-MM-DD], /queue list or /queue remove <id|slug>")
+	}
+}
+
+// handleQueueAdd validates and queues a problem, optionally for a specific date
+func (b *Bot) handleQueueAdd(message *tgbotapi.Message, args []string) {
+	if len(args) == 0 || len(args) > 2 {
+		b.sendMessage(message.Chat.ID, "❌ Usage: /queue add <slug> [YYYY-MM-DD]")
+		return
+	}
+
+	slug := strings.Trim(args[0], "/")
+	problem, err := b.db.GetProblemBySlug(slug)
+	if err == sql.ErrNoRows {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Problem %s was not found in the problem list.", chat.Escape(slug)))
+		return
+	}
+	if err != nil {
+		log.Printf("Error getting problem %s: %v", slug, err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while looking up the problem.")
+		return
+	}
+	if problem.Used {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ %s has already been used as a daily challenge.", chat.Escape(problem.Title)))
+		return
+	}
+
+	queued, err := b.db.IsProblemQueued(problem.ID)
+	if err != nil {
+		log.Printf("Error checking queue: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while checking the queue.")
+		return
+	}
+	if queued {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ %s is already in the queue.", chat.Escape(problem.Title)))
+		return
+	}
+
+	var date string
+	if len(args) == 2 {
+		scheduled, err := time.ParseInLocation("2006-01-02", args[1], b.location)
+		if err != nil {
+			b.sendMessage(message.Chat.ID, "❌ Invalid date, please use the YYYY-MM-DD format.")
+			return
+		}
+		date = scheduled.Format("2006-01-02")
+		if date < b.today() {
+			b.sendMessage(message.Chat.ID, "❌ The date must not be in the past.")
+			return
+		}
+		if weekday := scheduled.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+			b.sendMessage(message.Chat.ID, "❌ No challenges are posted on weekends, please pick a weekday.")
+			return
+		}
+
+		queue, err := b.db.GetQueue()
+		if err != nil {
+			log.Printf("Error getting queue: %v", err)
+			b.sendMessage(message.Chat.ID, "❌ An error occurred while checking the queue.")
+			return
+		}
+		for _, entry := range queue {
+			if entry.ScheduledDate == date {
+				b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ %s is already planned for %s.", chat.Escape(entry.Title), date))
+				return
+			}
+		}
+	}
+
+	if err := b.db.EnqueueProblem(problem.ID, date); err != nil {
+		log.Printf("Error queueing problem: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while adding the problem to the queue.")
+		return
+	}
+
+	when := "the next free day"
+	if date != "" {
+		when = date
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ **%s** has been queued for %s.", chat.Escape(problem.Title), when))
+}
+
+// handleQueueList shows the upcoming queued problems
+func (b *Bot) handleQueueList(message *tgbotapi.Message) {
+	queue, err := b.db.GetQueue()
+	if err != nil {
+		log.Printf("Error getting queue: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while fetching the queue.")
+		return
+	}
+
+	if len(queue) == 0 {
+		b.sendMessage(message.Chat.ID, "📭 The queue is empty. Daily challenges will be picked at random.")
+		return
+	}
+
+	var responseText strings.Builder
+	responseText.WriteString("🗓️ **Upcoming Challenges** 🗓️\n\n")
+
+	for _, entry := range queue {
+		when := "next free day"
+		if entry.ScheduledDate != "" {
+			when = entry.ScheduledDate
+		}
+		responseText.WriteString(fmt.Sprintf("#%d %s - %s\n", entry.ID, when, chat.Escape(entry.Title)))
+	}
+
+	b.sendMessage(message.Chat.ID, responseText.String())
+}
+
+// handleQueueRemove removes a queued problem by queue ID or slug
+func (b *Bot) handleQueueRemove(message *tgbotapi.Message, args []string) {
+	if len(args) != 1 {
+		b.sendMessage(message.Chat.ID, "❌ Usage: /queue remove <id|slug>")
+		return
+	}
+
+	if id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#")); err == nil {
+		removed, err := b.db.RemoveQueueEntry(id)
+		if err != nil {
+			log.Printf("Error removing queue entry: %v", err)
+			b.sendMessage(message.Chat.ID, "❌ An error occurred while removing the queue entry.")
+			return
+		}
+		if !removed {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ There is no queue entry #%d.", id))
+			return
+		}
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Queue entry #%d has been removed.", id))
+		return
+	}
+
+	slug := strings.Trim(args[0], "/")
+	problem, err := b.db.GetProblemBySlug(slug)
+	if err == sql.ErrNoRows {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Problem %s was not found in the problem list.", chat.Escape(slug)))
+		return
+	}
+	if err != nil {
+		log.Printf("Error getting problem %s: %v", slug, err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while looking up the problem.")
+		return
+	}
+
+	queued, err := b.db.IsProblemQueued(problem.ID)
+	if err != nil {
+		log.Printf("Error checking queue for problem %d: %v", problem.ID, err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while checking the queue.")
+		return
+	}
+	if !queued {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ %s is not in the queue.", chat.Escape(problem.Title)))
+		return
+	}
+
+	if err := b.db.DequeueProblem(problem.ID); err != nil {
+		log.Printf("Error removing problem from queue: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while removing the problem from the queue.")
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ **%s** has been removed from the queue.", chat.Escape(problem.Title)))
+}
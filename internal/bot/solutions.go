@@ -0,0 +1,255 @@
+package bot
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"leetcode-telegram-bot/internal/chat"
+	"leetcode-telegram-bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// maxSolutionLength keeps a shared solution, with its header, within one Telegram message
+const maxSolutionLength = 3500
+
+// solutionPromptPattern finds the language in the bot's "reply with your code" prompt
+var solutionPromptPattern = regexp.MustCompile(`Reply to this message with your (\S+) solution`)
+
+// handleSolutionCommand handles the /solution <language> command in private chats. The code
+// can follow the command directly, or be sent as a reply to the prompt the bot answers with.
+func (b *Bot) handleSolutionCommand(message *tgbotapi.Message) {
+	if !message.Chat.IsPrivate() {
+		b.sendMessage(message.Chat.ID, "🔒 Share solutions in private chat so nobody gets spoiled. Send me /solution <language> in a DM!")
+		return
+	}
+
+	args := strings.TrimSpace(message.CommandArguments())
+	if args == "" {
+		b.sendMessage(message.Chat.ID, "❌ Usage: /solution <language> followed by your code, e.g.\n/solution python\n```\nclass Solution: ...\n```")
+		return
+	}
+
+	language, code, _ := strings.Cut(args, "\n")
+	if fields := strings.Fields(language); len(fields) > 1 {
+		// Code on the same line as the language
+		language, code = fields[0], strings.TrimSpace(strings.TrimPrefix(args, fields[0]))
+	}
+	language = strings.ToLower(strings.TrimSpace(language))
+
+	if strings.TrimSpace(code) == "" {
+		prompt := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("📨 Reply to this message with your %s solution.", language))
+		prompt.ReplyToMessageID = message.MessageID
+		prompt.ReplyMarkup = tgbotapi.ForceReply{ForceReply: true, Selective: true}
+		if _, err := b.api.Send(prompt); err != nil {
+			log.Printf("Error sending solution prompt: %v", err)
+		}
+		return
+	}
+
+	b.saveSolution(message, language, code)
+}
+
+// handleReplyToBot handles non-command replies to the bot's messages in private chats
+func (b *Bot) handleReplyToBot(message *tgbotapi.Message) {
+	if !message.Chat.IsPrivate() {
+		return
+	}
+
+	match := solutionPromptPattern.FindStringSubmatch(message.ReplyToMessage.Text)
+	if match == nil {
+		return
+	}
+
+	b.saveSolution(message, match[1], message.Text)
+}
+
+// saveSolution stores a solution for today's challenge if the user has a verified solve
+func (b *Bot) saveSolution(message *tgbotapi.Message, language, code string) {
+	today := b.today()
+
+	code = stripCodeFence(code)
+	if code == "" {
+		b.sendMessage(message.Chat.ID, "❌ The solution is empty.")
+		return
+	}
+	if len(code) > maxSolutionLength {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ The solution is too long (max %d characters).", maxSolutionLength))
+		return
+	}
+
+	problem, err := b.db.GetTodaysChallenge(today)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ No challenge available for today yet.")
+		return
+	}
+
+	verified, err := b.db.HasVerifiedSubmission(message.From.ID, today)
+	if err != nil {
+		log.Printf("Error checking submission: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while checking your submission.")
+		return
+	}
+	if !verified {
+		b.sendMessage(message.Chat.ID, "❌ Only members with a verified solve of today's challenge can share solutions. Register with /register so I can spot your accepted submission.")
+		return
+	}
+
+	solution := &models.Solution{
+		UserID:    message.From.ID,
+		ProblemID: problem.ID,
+		Date:      today,
+		Language:  language,
+		Code:      code,
+	}
+	if err := b.db.SaveSolution(solution); err != nil {
+		log.Printf("Error saving solution: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while saving your solution.")
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Your %s solution for **%s** is saved. It will be shared with the group after %s.",
+		language, chat.Escape(problem.Title), b.config.SolutionRevealTime))
+}
+
+// handleSolutionsCommand handles the /solutions [day] command for browsing shared solutions.
+// In the group only revealed solutions are shown; in private chat, members who solved
+// the day themselves can see all of them.
+func (b *Bot) handleSolutionsCommand(message *tgbotapi.Message) {
+	var challenge *models.DailyChallenge
+	var err error
+	if arg := strings.TrimSpace(message.CommandArguments()); arg != "" {
+		dayNumber, convErr := strconv.Atoi(strings.TrimPrefix(strings.ToLower(arg), "day"))
+		if convErr != nil {
+			b.sendMessage(message.Chat.ID, "❌ Usage: /solutions [day number]")
+			return
+		}
+		challenge, err = b.db.GetDailyChallengeByDay(dayNumber)
+	} else {
+		challenge, err = b.db.GetDailyChallenge(b.today())
+	}
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ No challenge found for that day.")
+		return
+	}
+
+	solutions, err := b.db.GetSolutions(challenge.Date)
+	if err != nil {
+		log.Printf("Error getting solutions: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while fetching the solutions.")
+		return
+	}
+
+	showAll := false
+	if message.Chat.IsPrivate() {
+		showAll, err = b.db.HasVerifiedSubmission(message.From.ID, challenge.Date)
+		if err != nil {
+			log.Printf("Error checking submission: %v", err)
+		}
+	}
+
+	shown := 0
+	for _, solution := range solutions {
+		if !solution.Revealed && !showAll {
+			continue
+		}
+		b.sendSolution(message.Chat.ID, &solution, !message.Chat.IsPrivate())
+		shown++
+	}
+
+	if shown == 0 {
+		if len(solutions) > 0 {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("🙈 Day %d solutions are revealed after %s.", challenge.DayNumber, b.config.SolutionRevealTime))
+		} else {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("📭 No solutions have been shared for Day %d yet.", challenge.DayNumber))
+		}
+	}
+}
+
+// RevealSolutions posts shared solutions to every group once the reveal time has passed.
+// Solutions of earlier days that were never posted are revealed right away. The reveal time
+// is in the configured timezone, like the cron schedules.
+func (b *Bot) RevealSolutions() error {
+	now := b.now()
+	upTo := now.AddDate(0, 0, -1).Format("2006-01-02")
+	if now.Format("15:04") >= b.config.SolutionRevealTime {
+		upTo = now.Format("2006-01-02")
+	}
+
+	solutions, err := b.db.GetUnrevealedSolutions(upTo)
+	if err != nil {
+		return fmt.Errorf("failed to get unrevealed solutions: %w", err)
+	}
+
+	for _, solution := range solutions {
+		if err := b.revealSolution(&solution); err != nil {
+			log.Printf("Error revealing solution %d: %v", solution.ID, err)
+			continue
+		}
+		if err := b.db.MarkSolutionRevealed(solution.ID); err != nil {
+			log.Printf("Error marking solution %d as revealed: %v", solution.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// revealSolution posts a solution to every group, behind a spoiler on Telegram. Slack and
+// Discord have no spoilers to put it behind, so it is shown as code. It only fails when no
+// group got the solution, so that one unreachable platform doesn't post it again elsewhere.
+func (b *Bot) revealSolution(solution *models.Solution) error {
+	var failed []string
+	for _, group := range b.groups {
+		platform := group.Platform.Name()
+		var err error
+		if platform == chat.PlatformTelegram {
+			err = b.sendSolution(b.config.TelegramGroupID, solution, true)
+		} else {
+			_, err = group.Platform.Send(group.ChatID, fmt.Sprintf("💡 **Day %d solution by %s** (%s)\n`%s`",
+				solution.DayNumber, chat.Escape(solution.FirstName), chat.Escape(solution.Language), chat.Escape(solution.Code)))
+		}
+		if err != nil {
+			log.Printf("Error revealing solution %d on %s: %v", solution.ID, platform, err)
+			failed = append(failed, platform)
+		}
+	}
+	if len(failed) == len(b.groups) {
+		return fmt.Errorf("failed to send to %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// sendSolution posts a solution, optionally hidden behind a spoiler
+func (b *Bot) sendSolution(chatID int64, solution *models.Solution, spoiler bool) error {
+	header := fmt.Sprintf("💡 <b>Day %d solution by %s</b> (%s)\n",
+		solution.DayNumber, html.EscapeString(solution.FirstName), html.EscapeString(solution.Language))
+	code := "<pre>" + html.EscapeString(solution.Code) + "</pre>"
+	if spoiler {
+		code = "<tg-spoiler>" + code + "</tg-spoiler>"
+	}
+
+	msg := tgbotapi.NewMessage(chatID, header+code)
+	msg.ParseMode = tgbotapi.ModeHTML
+	if _, err := b.api.Send(msg); err != nil {
+		return err
+	}
+	return nil
+}
+
+// stripCodeFence removes surrounding ``` fences (and a language tag after the opening fence)
+func stripCodeFence(code string) string {
+	code = strings.TrimSpace(code)
+	if !strings.HasPrefix(code, "```") || !strings.HasSuffix(code, "```") || len(code) < 6 {
+		return code
+	}
+
+	code = strings.TrimSuffix(strings.TrimPrefix(code, "```"), "```")
+	if firstLine, rest, found := strings.Cut(code, "\n"); found && !strings.ContainsAny(strings.TrimSpace(firstLine), " \t(){};=") {
+		code = rest
+	}
+	return strings.TrimSpace(code)
+}
@@ -0,0 +1,146 @@
+package bot
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"leetcode-telegram-bot/internal/achievements"
+	"leetcode-telegram-bot/internal/chat"
+	"leetcode-telegram-bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleStatsCommand handles the /stats [@user] command. Without a username it shows the
+// stats of the sender, or of the author of the message being replied to.
+// /stats export sends the stats of all users as a JSON file.
+func (b *Bot) handleStatsCommand(message *tgbotapi.Message) {
+	arg := strings.TrimSpace(message.CommandArguments())
+	if strings.EqualFold(arg, "export") {
+		b.handleStatsExport(message)
+		return
+	}
+
+	userID := message.From.ID
+	if arg != "" {
+		user, err := b.db.GetUserByUsername(strings.TrimPrefix(arg, "@"))
+		if err == sql.ErrNoRows {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ I don't know %s yet.", arg))
+			return
+		}
+		if err != nil {
+			log.Printf("Error getting user %s: %v", arg, err)
+			b.sendMessage(message.Chat.ID, "❌ An error occurred while looking up the user.")
+			return
+		}
+		userID = user.ID
+	} else if reply := message.ReplyToMessage; reply != nil && reply.From != nil && !reply.From.IsBot {
+		userID = reply.From.ID
+	}
+
+	stats, err := b.db.GetUserStats(userID, b.today())
+	if err != nil {
+		log.Printf("Error getting stats for user %d: %v", userID, err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while fetching the stats.")
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, formatUserStats(stats))
+}
+
+// handleStatsExport sends the stats of all users as a JSON document
+func (b *Bot) handleStatsExport(message *tgbotapi.Message) {
+	users, err := b.db.GetAllUsers()
+	if err != nil {
+		log.Printf("Error getting users: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while exporting the stats.")
+		return
+	}
+
+	today := b.today()
+	export := make([]*models.UserStats, 0, len(users))
+	for _, user := range users {
+		stats, err := b.db.GetUserStats(user.ID, today)
+		if err != nil {
+			log.Printf("Error getting stats for user %d: %v", user.ID, err)
+			continue
+		}
+		export = append(export, stats)
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		log.Printf("Error encoding stats: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while exporting the stats.")
+		return
+	}
+
+	document := tgbotapi.NewDocument(message.Chat.ID, tgbotapi.FileBytes{
+		Name:  fmt.Sprintf("stats-%s.json", today),
+		Bytes: data,
+	})
+	document.Caption = fmt.Sprintf("📊 Stats of %d members", len(export))
+	if _, err := b.api.Send(document); err != nil {
+		log.Printf("Error sending stats export: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while sending the export.")
+	}
+}
+
+// formatUserStats formats a user's stats for chat
+func formatUserStats(stats *models.UserStats) string {
+	name := stats.User.FirstName
+	if stats.User.LastName != "" {
+		name += " " + stats.User.LastName
+	}
+	name = chat.Escape(name)
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("📊 **Stats for %s** 📊\n\n", name))
+
+	if stats.TotalSolved == 0 {
+		text.WriteString("No solves yet. Today is a great day to start! 💪")
+		return text.String()
+	}
+
+	text.WriteString(fmt.Sprintf("✅ Solved: %d (%d verified, %d self-reported)\n", stats.TotalSolved, stats.Verified, stats.SelfReported))
+	text.WriteString(fmt.Sprintf("🔥 Streak: %d current, %d longest\n", stats.CurrentStreak, stats.LongestStreak))
+	text.WriteString(fmt.Sprintf("⏱️ Average time to solve: %s\n", formatSolveTime(time.Duration(stats.AverageSolveSeconds)*time.Second)))
+	text.WriteString(fmt.Sprintf("📈 Participation: %.0f%% (%d of %d challenges since joining)\n",
+		stats.ParticipationRate*100, stats.SolvedSinceJoin, stats.ChallengesSinceJoin))
+
+	categories := make([]string, 0, len(stats.ByCategory))
+	for category := range stats.ByCategory {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if stats.ByCategory[categories[i]] != stats.ByCategory[categories[j]] {
+			return stats.ByCategory[categories[i]] > stats.ByCategory[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
+
+	text.WriteString("\n🏷️ **By category:**\n")
+	for _, category := range categories {
+		text.WriteString(fmt.Sprintf("• %s: %d\n", category, stats.ByCategory[category]))
+	}
+
+	if len(stats.Achievements) > 0 {
+		text.WriteString(fmt.Sprintf("\n🏅 **Badges (%d/%d):** %s\n", len(stats.Achievements), len(achievements.All), formatAchievements(stats.Achievements)))
+	}
+
+	return text.String()
+}
+
+// formatSolveTime formats a duration as hours and minutes
+func formatSolveTime(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,120 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"leetcode-telegram-bot/internal/chat"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleTrackCommand handles the /track command and its list, on and off subcommands
+func (b *Bot) handleTrackCommand(message *tgbotapi.Message) {
+	if !b.isGroupAdminMessage(message) {
+		return
+	}
+
+	usage := "❌ Usage: /track list, /track on <YYYY-MM-DD> <YYYY-MM-DD> <name> or /track off <name>"
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 {
+		b.sendMessage(message.Chat.ID, usage)
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "list":
+		b.handleTrackList(message)
+	case "on":
+		if len(args) < 4 {
+			b.sendMessage(message.Chat.ID, "❌ Usage: /track on <YYYY-MM-DD> <YYYY-MM-DD> <name>")
+			return
+		}
+		b.handleTrackOn(message, args[1], args[2], strings.Join(args[3:], " "))
+	case "off":
+		if len(args) < 2 {
+			b.sendMessage(message.Chat.ID, "❌ Usage: /track off <name>")
+			return
+		}
+		b.handleTrackOff(message, strings.Join(args[1:], " "))
+	default:
+		b.sendMessage(message.Chat.ID, usage)
+	}
+}
+
+// handleTrackList shows all tracks, their date ranges and progress
+func (b *Bot) handleTrackList(message *tgbotapi.Message) {
+	tracks, err := b.db.GetTracks()
+	if err != nil {
+		log.Printf("Error getting tracks: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while fetching the tracks.")
+		return
+	}
+
+	if len(tracks) == 0 {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("📭 No tracks defined. Add them to %s.", b.config.TracksFilePath))
+		return
+	}
+
+	var responseText strings.Builder
+	responseText.WriteString("🛤️ **Tracks** 🛤️\n\n")
+
+	for _, track := range tracks {
+		when := "not scheduled"
+		if track.StartDate != "" {
+			when = fmt.Sprintf("%s → %s", track.StartDate, track.EndDate)
+		}
+		responseText.WriteString(fmt.Sprintf("• %s - %d/%d posted (%s)\n", chat.Escape(track.Name), track.Posted, track.Total, when))
+	}
+
+	b.sendMessage(message.Chat.ID, responseText.String())
+}
+
+// handleTrackOn turns a track on for a date range
+func (b *Bot) handleTrackOn(message *tgbotapi.Message, startArg, endArg, name string) {
+	start, err := time.Parse("2006-01-02", startArg)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Invalid start date, please use the YYYY-MM-DD format.")
+		return
+	}
+	end, err := time.Parse("2006-01-02", endArg)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Invalid end date, please use the YYYY-MM-DD format.")
+		return
+	}
+	if end.Before(start) {
+		b.sendMessage(message.Chat.ID, "❌ The end date must not be before the start date.")
+		return
+	}
+
+	found, err := b.db.SetTrackDates(name, startArg, endArg)
+	if err != nil {
+		log.Printf("Error setting track dates: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while scheduling the track.")
+		return
+	}
+	if !found {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Track %s was not found. Use /track list to see available tracks.", chat.Escape(name)))
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Track **%s** will run from %s to %s.", chat.Escape(name), startArg, endArg))
+}
+
+// handleTrackOff stops a track by clearing its date range
+func (b *Bot) handleTrackOff(message *tgbotapi.Message, name string) {
+	found, err := b.db.SetTrackDates(name, "", "")
+	if err != nil {
+		log.Printf("Error clearing track dates: %v", err)
+		b.sendMessage(message.Chat.ID, "❌ An error occurred while stopping the track.")
+		return
+	}
+	if !found {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Track %s was not found. Use /track list to see available tracks.", chat.Escape(name)))
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Track **%s** has been turned off.", chat.Escape(name)))
+}
@@ -0,0 +1,96 @@
+package bot
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+
+	"leetcode-telegram-bot/internal/chat"
+	"leetcode-telegram-bot/internal/judge"
+	"leetcode-telegram-bot/internal/leetcode"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleVerifyCommand handles the /verify [platform] command. The linked LeetCode account is verified
+// once the token issued by /register shows up in its "About me" or "Real name".
+func (b *Bot) handleVerifyCommand(message *tgbotapi.Message) {
+	if arg := strings.TrimSpace(message.CommandArguments()); arg != "" {
+		platform, err := judge.ParsePlatform(arg)
+		if err != nil {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Unknown platform %s.", arg))
+			return
+		}
+		if platform != judge.LeetCode {
+			b.handleVerifyJudgeAccount(message, platform)
+			return
+		}
+	}
+
+	b.sendReply(message, b.verifyLeetcode(message.From.ID))
+}
+
+// verifyLeetcode verifies the LeetCode account linked to a user once the token issued by
+// /register shows up on its profile
+func (b *Bot) verifyLeetcode(userID int64) chat.Reply {
+	link, err := b.db.GetLeetcodeLink(userID)
+	if err == sql.ErrNoRows {
+		return chat.Reply{Text: "❌ Please register your LeetCode username first with /register <leetcode_username>."}
+	}
+	if err != nil {
+		log.Printf("Error getting LeetCode profile: %v", err)
+		return chat.Reply{Text: "❌ An error occurred while verifying your LeetCode username."}
+	}
+	if link.Verified {
+		return chat.Reply{Text: fmt.Sprintf("✅ Your LeetCode username %s is already verified.", chat.Escape(link.LeetCodeUsername))}
+	}
+
+	if link.VerificationToken == "" {
+		return chat.Reply{Text: "❌ Your link has no verification token. Please /register your LeetCode username again."}
+	}
+
+	site := leetcode.Site(link.Site)
+	profile, err := leetcode.GetUserProfile(site, link.LeetCodeUsername)
+	if err != nil {
+		log.Printf("Error getting LeetCode profile of %s: %v", link.LeetCodeUsername, err)
+		return chat.Reply{Text: "❌ Could not reach LeetCode, please try again later."}
+	}
+
+	if !strings.Contains(profile.AboutMe, link.VerificationToken) && !strings.Contains(profile.RealName, link.VerificationToken) {
+		return chat.Reply{
+			Text: "❌ Not verified yet.",
+			Private: "❌ I couldn't find the token on your LeetCode profile yet. Changes can take a minute to show up.\n\n" +
+				verificationInstructions(site, link.LeetCodeUsername, link.VerificationToken),
+		}
+	}
+
+	if err := b.db.MarkLeetcodeProfileVerified(userID); err != nil {
+		log.Printf("Error verifying LeetCode profile: %v", err)
+		return chat.Reply{Text: "❌ An error occurred while verifying your LeetCode username."}
+	}
+
+	return chat.Reply{
+		Text: "🔓 LeetCode username verified.",
+		Private: fmt.Sprintf("🔓 Your LeetCode username **%s** is verified! Your daily solves will now be detected automatically. "+
+			"You can remove the token from your profile.", chat.Escape(link.LeetCodeUsername)),
+	}
+}
+
+// newVerificationToken generates a random token for proving ownership of a LeetCode account
+func newVerificationToken() (string, error) {
+	bytes := make([]byte, 4)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return "lcbot-" + hex.EncodeToString(bytes), nil
+}
+
+// verificationInstructions explains how to verify a LeetCode account with the given token
+func verificationInstructions(site leetcode.Site, username, token string) string {
+	return fmt.Sprintf("🔐 To prove %s is yours, add this token to your %s profile's \"About me\" or \"Real name\" "+
+		"at %s and then send /verify:\n\n`%s`\n\n"+
+		"Until then your solves are only counted as self-reported.", chat.Escape(username), site, site.ProfileSettingsURL(), token)
+}
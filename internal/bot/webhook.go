@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"leetcode-telegram-bot/internal/server"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// secretTokenHeader is the header Telegram sends the webhook secret token in
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// webhookPath is the path Telegram posts updates to. The secret in it keeps the
+// endpoint from being guessed.
+func (b *Bot) webhookPath() string {
+	return "/telegram/" + b.config.WebhookSecret
+}
+
+// RegisterHandlers adds the bot's HTTP endpoints to the server: the Telegram webhook in
+// webhook mode, and the command endpoints of the Slack and Discord adapters when configured
+func (b *Bot) RegisterHandlers(s *server.Server) {
+	if b.config.WebhookURL != "" {
+		s.Handle(b.webhookPath(), http.HandlerFunc(b.handleWebhook))
+	}
+	if b.slack != nil {
+		s.Handle("/slack/commands", b.slack.CommandHandler(b.handleChatCommand))
+	}
+	if b.discord != nil {
+		s.Handle("/discord/interactions", b.discord.CommandHandler(b.handleChatCommand))
+	}
+}
+
+// serveWebhook registers the webhook with Telegram and waits until ctx is cancelled,
+// then removes the webhook again unless WEBHOOK_DELETE_ON_SHUTDOWN is off. Updates arrive
+// through the HTTP server.
+func (b *Bot) serveWebhook(ctx context.Context) error {
+	if err := b.setWebhook(); err != nil {
+		return err
+	}
+	log.Printf("Receiving updates through the webhook on %s", b.config.HTTPAddr)
+
+	<-ctx.Done()
+	log.Println("Bot stopping...")
+	if b.config.WebhookDeleteOnShutdown {
+		b.deleteWebhook()
+	}
+	return nil
+}
+
+// setWebhook tells Telegram to post updates to the webhook with the secret token header.
+// The secret token isn't part of WebhookConfig in this version of the library.
+func (b *Bot) setWebhook() error {
+	params := tgbotapi.Params{
+		"url":          strings.TrimSuffix(b.config.WebhookURL, "/") + b.webhookPath(),
+		"secret_token": b.config.WebhookSecret,
+	}
+	if _, err := b.api.MakeRequest("setWebhook", params); err != nil {
+		return fmt.Errorf("failed to set webhook: %w", err)
+	}
+	return nil
+}
+
+// deleteWebhook tells Telegram to stop posting updates
+func (b *Bot) deleteWebhook() {
+	if _, err := b.api.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		log.Printf("Error deleting webhook: %v", err)
+	}
+}
+
+// handleWebhook decodes an update posted by Telegram and dispatches it
+func (b *Bot) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get(secretTokenHeader)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(b.config.WebhookSecret)) != 1 {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	update, err := b.api.HandleUpdate(r)
+	if err != nil {
+		log.Printf("Error decoding webhook update: %v", err)
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	b.dispatch(*update)
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,63 @@
+package chat
+
+import "strings"
+
+// Names of the supported chat platforms
+const (
+	PlatformTelegram = "telegram"
+	PlatformSlack    = "slack"
+	PlatformDiscord  = "discord"
+)
+
+// Platform is a chat service the challenge program can run on. Message texts use the
+// bot's Markdown (**bold**, `code`, \ before a character to show it literally), which each
+// adapter converts to its own format. Names, titles and other text not written by the bot
+// go through Escape before being inserted.
+type Platform interface {
+	// Name returns the platform's name, e.g. "slack"
+	Name() string
+	// Send posts a message to a chat and returns the ID of the message
+	Send(chatID, text string) (string, error)
+	// Edit replaces the text of a message sent earlier
+	Edit(chatID, messageID, text string) error
+	// Mention formats a mention of a user that notifies them
+	Mention(user User) string
+}
+
+// User is a member as known to a chat platform
+type User struct {
+	ID       string
+	Username string
+	Name     string
+}
+
+// Command is a command received from a chat platform, e.g. "/leetcode register alice"
+type Command struct {
+	Platform string
+	ChatID   string
+	User     User
+	Name     string // Lowercase name of the command, e.g. "register"
+	Args     string
+}
+
+// Reply is the answer to a command. Text is shown in the chat the command came from,
+// unless Private is set, which is shown only to the user who sent the command.
+type Reply struct {
+	Text    string
+	Private string
+}
+
+// CommandHandler answers commands received from a chat platform
+type CommandHandler func(Command) Reply
+
+// Group is a chat that runs the challenge program
+type Group struct {
+	Platform Platform
+	ChatID   string
+}
+
+// parseCommandText splits the text of a slash command into the subcommand and its arguments
+func parseCommandText(text string) (string, string) {
+	name, args, _ := strings.Cut(strings.TrimSpace(text), " ")
+	return strings.ToLower(name), strings.TrimSpace(args)
+}
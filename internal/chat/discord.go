@@ -0,0 +1,255 @@
+package chat
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// discordAPIURL is the base URL of the Discord REST API
+const discordAPIURL = "https://discord.com/api/v10"
+
+// Discord interaction and response types
+const (
+	discordInteractionPing    = 1
+	discordInteractionCommand = 2
+
+	discordResponsePong    = 1
+	discordResponseMessage = 4
+
+	// discordFlagEphemeral shows a response only to the user who sent the command
+	discordFlagEphemeral = 64
+)
+
+// discordMaxLength is the longest message content Discord accepts
+const discordMaxLength = 2000
+
+// discordCommand is the name of the slash command the bot registers
+const discordCommand = "leetcode"
+
+// Discord is the Discord adapter. Messages are sent with the REST API and commands arrive as
+// interactions of the /leetcode slash command, signed with the application's public key.
+type Discord struct {
+	token         string
+	applicationID string
+	publicKey     ed25519.PublicKey
+	client        *http.Client
+}
+
+// NewDiscord creates the Discord adapter from a bot token, the application ID and the
+// application's hex-encoded public key
+func NewDiscord(token, applicationID, publicKey string) (*Discord, error) {
+	key, err := hex.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid Discord public key, expected %d hex-encoded bytes", ed25519.PublicKeySize)
+	}
+
+	return &Discord{
+		token:         token,
+		applicationID: applicationID,
+		publicKey:     ed25519.PublicKey(key),
+		client:        &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Name returns "discord"
+func (d *Discord) Name() string {
+	return PlatformDiscord
+}
+
+// Send posts a message to a channel. Messages over Discord's length limit are sent in parts
+// and the ID of the last one is returned.
+func (d *Discord) Send(chatID, text string) (string, error) {
+	var message struct {
+		ID string `json:"id"`
+	}
+	for _, part := range splitMessage(text, discordMaxLength, discordLength) {
+		err := d.call(http.MethodPost, "/channels/"+chatID+"/messages", discordMessage(discordText(part)), &message)
+		if err != nil {
+			return "", err
+		}
+	}
+	return message.ID, nil
+}
+
+// Edit replaces the text of a message. Text over the length limit is cut short.
+func (d *Discord) Edit(chatID, messageID, text string) error {
+	return d.call(http.MethodPatch, "/channels/"+chatID+"/messages/"+messageID, discordMessage(discordText(truncateMessage(text, discordMaxLength, discordLength))), nil)
+}
+
+// Mention mentions a user by ID
+func (d *Discord) Mention(user User) string {
+	return "<@" + user.ID + ">"
+}
+
+// discordMessage builds a message body that only notifies mentioned users, never @everyone
+func discordMessage(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"content":          text,
+		"allowed_mentions": map[string][]string{"parse": {"users"}},
+	}
+}
+
+// RegisterCommand creates or updates the /leetcode slash command of the application
+func (d *Discord) RegisterCommand() error {
+	command := map[string]interface{}{
+		"name":        discordCommand,
+		"description": "LeetCode daily challenge",
+		"options": []map[string]interface{}{{
+			"type":        3, // String
+			"name":        "command",
+			"description": "today, leaderboard, solved, register <username> [com|cn], verify or help",
+			"required":    false,
+		}},
+	}
+	return d.call(http.MethodPost, "/applications/"+d.applicationID+"/commands", command, nil)
+}
+
+// call calls the REST API with a JSON body and decodes the response into result if given
+func (d *Discord) call(method, path string, body, result interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, discordAPIURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bot "+d.token)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("discord %s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("discord %s %s returned %s: %s", method, path, resp.Status, detail)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// discordInteraction is the part of an interaction the adapter uses
+type discordInteraction struct {
+	Type      int    `json:"type"`
+	ChannelID string `json:"channel_id"`
+	Data      struct {
+		Options []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"options"`
+	} `json:"data"`
+	// Member is set for commands sent in a server, User for direct messages
+	Member *struct {
+		User discordUser `json:"user"`
+	} `json:"member"`
+	User *discordUser `json:"user"`
+}
+
+type discordUser struct {
+	ID         string `json:"id"`
+	Username   string `json:"username"`
+	GlobalName string `json:"global_name"`
+}
+
+// CommandHandler serves the interactions endpoint, checking the request signature
+func (d *Discord) CommandHandler(handle CommandHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if !d.verify(r.Header, body) {
+			http.Error(w, "invalid request signature", http.StatusUnauthorized)
+			return
+		}
+
+		var interaction discordInteraction
+		if err := json.Unmarshal(body, &interaction); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		var response map[string]interface{}
+		switch interaction.Type {
+		case discordInteractionPing:
+			response = map[string]interface{}{"type": discordResponsePong}
+		case discordInteractionCommand:
+			response = d.answer(interaction, handle)
+		default:
+			http.Error(w, "unsupported interaction", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error answering Discord interaction: %v", err)
+		}
+	})
+}
+
+// answer runs a slash command and builds the interaction response
+func (d *Discord) answer(interaction discordInteraction, handle CommandHandler) map[string]interface{} {
+	var text string
+	for _, option := range interaction.Data.Options {
+		if option.Name == "command" {
+			text = option.Value
+		}
+	}
+
+	var user discordUser
+	if interaction.Member != nil {
+		user = interaction.Member.User
+	} else if interaction.User != nil {
+		user = *interaction.User
+	}
+	name := user.GlobalName
+	if name == "" {
+		name = user.Username
+	}
+
+	command, args := parseCommandText(text)
+	reply := handle(Command{
+		Platform: PlatformDiscord,
+		ChatID:   interaction.ChannelID,
+		User:     User{ID: user.ID, Username: user.Username, Name: name},
+		Name:     command,
+		Args:     args,
+	})
+
+	data := map[string]interface{}{
+		"content":          truncateMessage(reply.Text, discordMaxLength, discordLength),
+		"allowed_mentions": map[string][]string{"parse": {}},
+	}
+	if reply.Private != "" {
+		data["content"] = truncateMessage(reply.Private, discordMaxLength, discordLength)
+		data["flags"] = discordFlagEphemeral
+	}
+	return map[string]interface{}{"type": discordResponseMessage, "data": data}
+}
+
+// verify checks the Ed25519 signature of "<timestamp><body>" in the X-Signature-Ed25519 header
+func (d *Discord) verify(header http.Header, body []byte) bool {
+	signature, err := hex.DecodeString(header.Get("X-Signature-Ed25519"))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return false
+	}
+	timestamp := header.Get("X-Signature-Timestamp")
+	if timestamp == "" {
+		return false
+	}
+	return ed25519.Verify(d.publicKey, append([]byte(timestamp), body...), signature)
+}
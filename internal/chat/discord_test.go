@@ -0,0 +1,64 @@
+package chat
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"net/http"
+	"testing"
+)
+
+func TestDiscordVerify(t *testing.T) {
+	const body = `{"type":1}`
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{1}, ed25519.SeedSize))
+	otherKey := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{2}, ed25519.SeedSize))
+	sign := func(key ed25519.PrivateKey, message string) string {
+		return hex.EncodeToString(ed25519.Sign(key, []byte(message)))
+	}
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		body      string
+		want      bool
+	}{
+		{"valid", "1700000000", sign(key, "1700000000"+body), body, true},
+		{"other key", "1700000000", sign(otherKey, "1700000000"+body), body, false},
+		{"tampered body", "1700000000", sign(key, "1700000000"+body), body + " ", false},
+		{"other timestamp", "1700000001", sign(key, "1700000000"+body), body, false},
+		{"no timestamp", "", sign(key, body), body, false},
+		{"not hex", "1700000000", "zz", body, false},
+		{"truncated", "1700000000", sign(key, "1700000000"+body)[:64], body, false},
+		{"no signature", "1700000000", "", body, false},
+	}
+	d, err := NewDiscord("token", "app", hex.EncodeToString(key.Public().(ed25519.PublicKey)))
+	if err != nil {
+		t.Fatalf("NewDiscord: %v", err)
+	}
+	for _, tt := range tests {
+		header := http.Header{}
+		header.Set("X-Signature-Timestamp", tt.timestamp)
+		header.Set("X-Signature-Ed25519", tt.signature)
+		if got := d.verify(header, []byte(tt.body)); got != tt.want {
+			t.Errorf("%s: verify = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewDiscordPublicKey(t *testing.T) {
+	tests := []struct {
+		key   string
+		valid bool
+	}{
+		{hex.EncodeToString(make([]byte, ed25519.PublicKeySize)), true},
+		{hex.EncodeToString(make([]byte, ed25519.PublicKeySize-1)), false},
+		{"not hex", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if _, err := NewDiscord("token", "app", tt.key); (err == nil) != tt.valid {
+			t.Errorf("NewDiscord(%q) error = %v, want valid %v", tt.key, err, tt.valid)
+		}
+	}
+}
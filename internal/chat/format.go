@@ -0,0 +1,291 @@
+package chat
+
+import (
+	"html"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// escapable are the characters Escape puts a backslash before. Besides the bot's own
+// ** and `, Discord also gives _, ~ and | a meaning.
+const escapable = "\\*_`~|"
+
+// Escape makes user-supplied or problem text, such as names and titles, show up literally
+// when it is inserted into a message written in the bot's Markdown
+func Escape(text string) string {
+	var escaped strings.Builder
+	for _, r := range text {
+		if strings.ContainsRune(escapable, r) {
+			escaped.WriteByte('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
+
+// segment is a piece of a message with the same formatting
+type segment struct {
+	text string
+	bold bool
+	code bool
+}
+
+// parseMarkdown splits a message written in the bot's Markdown into segments. ** toggles
+// bold, ` toggles code and a backslash makes the next character literal. Unclosed
+// formatting simply ends with the message.
+func parseMarkdown(text string) []segment {
+	var segments []segment
+	var current strings.Builder
+	var bold, code bool
+
+	flush := func() {
+		if current.Len() > 0 {
+			segments = append(segments, segment{text: current.String(), bold: bold, code: code})
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '\\' && i+1 < len(text) && strings.IndexByte(escapable, text[i+1]) >= 0:
+			i++
+			current.WriteByte(text[i])
+		case text[i] == '`':
+			flush()
+			code = !code
+		case !code && strings.HasPrefix(text[i:], "**"):
+			flush()
+			bold = !bold
+			i++
+		default:
+			current.WriteByte(text[i])
+		}
+	}
+	flush()
+
+	return segments
+}
+
+// PlainText removes the formatting from a message written in the bot's Markdown
+func PlainText(text string) string {
+	var plain strings.Builder
+	for _, s := range parseMarkdown(text) {
+		plain.WriteString(s.text)
+	}
+	return plain.String()
+}
+
+// telegramHTML converts a message written in the bot's Markdown to Telegram's HTML. Every
+// segment is escaped and closes its own tags, so the result always parses.
+func telegramHTML(text string) string {
+	var out strings.Builder
+	for _, s := range parseMarkdown(text) {
+		part := html.EscapeString(s.text)
+		if s.code {
+			part = "<code>" + part + "</code>"
+		}
+		if s.bold {
+			part = "<b>" + part + "</b>"
+		}
+		out.WriteString(part)
+	}
+	return out.String()
+}
+
+// slackText converts a message written in the bot's Markdown to Slack's mrkdwn, which uses
+// single asterisks for bold and has no escapes
+func slackText(text string) string {
+	var out strings.Builder
+	for _, s := range parseMarkdown(text) {
+		part := s.text
+		if s.code {
+			part = "`" + part + "`"
+		}
+		if s.bold {
+			part = "*" + part + "*"
+		}
+		out.WriteString(part)
+	}
+	return out.String()
+}
+
+// discordText converts a message written in the bot's Markdown for Discord, which reads the
+// same Markdown except inside code, where it shows backslashes as they are. Escapes in code
+// are dropped, and an escaped backtick, which would end the code, becomes a look-alike.
+func discordText(text string) string {
+	var out strings.Builder
+	var code bool
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '\\' && i+1 < len(text) && strings.IndexByte(escapable, text[i+1]) >= 0:
+			i++
+			switch {
+			case !code:
+				out.WriteString(text[i-1 : i+1])
+			case text[i] == '`':
+				out.WriteString("ˋ")
+			default:
+				out.WriteByte(text[i])
+			}
+		case text[i] == '`':
+			code = !code
+			out.WriteByte('`')
+		default:
+			out.WriteByte(text[i])
+		}
+	}
+	return out.String()
+}
+
+// telegramLength returns the length of a message the way Telegram counts it: in UTF-16 code
+// units of the text without formatting
+func telegramLength(text string) int {
+	return len(utf16.Encode([]rune(PlainText(text))))
+}
+
+// discordLength returns the length of a message the way Discord counts it, formatting included
+func discordLength(text string) int {
+	return len(utf16.Encode([]rune(text)))
+}
+
+// splitMessage splits a message written in the bot's Markdown into parts no longer than
+// limit, as measured by textLength, breaking between lines where possible. Bold or code
+// that is open where the message is split is closed at the end of the part and opened
+// again at the start of the next one, so every part renders on its own.
+func splitMessage(text string, limit int, textLength func(string) int) []string {
+	if textLength(text) <= limit {
+		return []string{text}
+	}
+
+	// Room for the markers around a part, and for escaping an asterisk before a closing **
+	budget := limit - textLength(opening(true, true)+closing(true, true)) - 1
+
+	var parts []string
+	var bold, code bool // Formatting open at the start of the current part
+	add := func(piece string) {
+		part := opening(bold, code) + piece
+		bold, code = formatting(piece, bold, code)
+		if bold && !code && endsWithAsterisk(part) {
+			// The asterisk would otherwise pair up with the closing **
+			part = part[:len(part)-1] + "\\*"
+		}
+		parts = append(parts, part+closing(bold, code))
+	}
+
+	var current string
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if textLength(opening(bold, code)+current+line) <= budget {
+			current += line
+			continue
+		}
+		if strings.TrimSpace(current) != "" {
+			add(strings.TrimRight(current, "\n"))
+		}
+		current = ""
+		// A single line that doesn't fit is broken wherever it has to be
+		for textLength(opening(bold, code)+line) > budget {
+			cut := cutAt(line, budget)
+			add(line[:cut])
+			line = line[cut:]
+		}
+		current = line
+	}
+	if strings.TrimSpace(current) != "" || len(parts) == 0 {
+		add(strings.TrimRight(current, "\n"))
+	}
+
+	return parts
+}
+
+// truncateMessage shortens a message written in the bot's Markdown to at most limit, as
+// measured by textLength, ending it with an ellipsis when something was cut off
+func truncateMessage(text string, limit int, textLength func(string) int) string {
+	if textLength(text) <= limit {
+		return text
+	}
+	return splitMessage(text, limit-1, textLength)[0] + "…"
+}
+
+// formatting returns whether bold and code are open after text, given whether they were
+// open before it
+func formatting(text string, bold, code bool) (bool, bool) {
+	for i := 0; i < len(text); i = nextToken(text, i) {
+		switch {
+		case text[i] == '`':
+			code = !code
+		case !code && strings.HasPrefix(text[i:], "**"):
+			bold = !bold
+		}
+	}
+	return bold, code
+}
+
+// opening returns the markers that open the given formatting
+func opening(bold, code bool) string {
+	var markers string
+	if bold {
+		markers += "**"
+	}
+	if code {
+		markers += "`"
+	}
+	return markers
+}
+
+// closing returns the markers that close the given formatting. Code can only be opened
+// inside bold, not the other way around, so it is closed first.
+func closing(bold, code bool) string {
+	var markers string
+	if code {
+		markers += "`"
+	}
+	if bold {
+		markers += "**"
+	}
+	return markers
+}
+
+// endsWithAsterisk reports whether text ends with a single, unescaped asterisk
+func endsWithAsterisk(text string) bool {
+	last := 0
+	for i := 0; i < len(text); i = nextToken(text, i) {
+		last = i
+	}
+	return text != "" && text[last:] == "*"
+}
+
+// nextToken returns where the token starting at offset i of text ends. An escape and a **
+// are a single token, so they are never split; anything else is one character.
+func nextToken(text string, i int) int {
+	switch {
+	case text[i] == '\\' && i+1 < len(text) && strings.IndexByte(escapable, text[i+1]) >= 0:
+		return i + 2
+	case strings.HasPrefix(text[i:], "**"):
+		return i + 2
+	}
+	_, size := utf8.DecodeRuneInString(text[i:])
+	return i + size
+}
+
+// cutAt returns the byte offset of a prefix of text whose UTF-16 length, formatting included,
+// fits within limit, never cutting a character, an escape or a ** in half. That is never
+// shorter than the length Telegram counts, so the prefix fits both platforms. At least one
+// token is kept so the text always gets shorter.
+func cutAt(text string, limit int) int {
+	length := 0
+	for i := 0; i < len(text); {
+		next := nextToken(text, i)
+		for _, r := range text[i:next] {
+			length++
+			if r > 0xFFFF {
+				length++
+			}
+		}
+		if length > limit && i > 0 {
+			return i
+		}
+		i = next
+	}
+	return len(text)
+}
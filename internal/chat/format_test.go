@@ -0,0 +1,217 @@
+package chat
+
+import (
+	"html"
+	"strings"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []string{
+		"plain",
+		"a*b_c~d|e`f",
+		`back\slash`,
+		"**not bold**",
+		"`not code`",
+		"trailing\\",
+	}
+	for _, text := range tests {
+		escaped := Escape(text)
+		if got := PlainText(escaped); got != text {
+			t.Errorf("PlainText(Escape(%q)) = %q, want the text back", text, got)
+		}
+		if got := telegramHTML("**" + escaped + "**"); got != "<b>"+html.EscapeString(text)+"</b>" {
+			t.Errorf("telegramHTML of bold %q = %q, want it as one bold segment", text, got)
+		}
+	}
+}
+
+func TestTelegramHTML(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"plain <text> & more", "plain &lt;text&gt; &amp; more"},
+		{"**Day 1** - Two Sum", "<b>Day 1</b> - Two Sum"},
+		{"`< 1h` ██", "<code>&lt; 1h</code> ██"},
+		{"**bold `code` bold**", "<b>bold </b><b><code>code</code></b><b> bold</b>"},
+		{"`code **not bold**`", "<code>code **not bold**</code>"},
+		{`\*literal\*`, "*literal*"},
+		{"**unclosed", "<b>unclosed</b>"},
+	}
+	for _, tt := range tests {
+		if got := telegramHTML(tt.text); got != tt.want {
+			t.Errorf("telegramHTML(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSlackText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"**Day 1** - Two Sum", "*Day 1* - Two Sum"},
+		{"`code`", "`code`"},
+		{`a\_b`, "a_b"},
+	}
+	for _, tt := range tests {
+		if got := slackText(tt.text); got != tt.want {
+			t.Errorf("slackText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestDiscordText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"**Day 1** - " + Escape("a_b*c"), `**Day 1** - a\_b\*c`},
+		{"`" + Escape("int *p = a[i]|b;") + "`", "`int *p = a[i]|b;`"},
+		{"`" + Escape("s = `x`") + "`", "`s = ˋxˋ`"},
+		{"`" + Escape(`"\n"`) + "` " + Escape(`\`), "`\"\\n\"` \\\\"},
+	}
+	for _, tt := range tests {
+		if got := discordText(tt.text); got != tt.want {
+			t.Errorf("discordText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSplitMessageShort(t *testing.T) {
+	text := "**Leaderboard**\n1. Ann"
+	parts := splitMessage(text, 100, telegramLength)
+	if len(parts) != 1 || parts[0] != text {
+		t.Errorf("splitMessage(%q) = %q, want the message unchanged", text, parts)
+	}
+}
+
+func TestSplitMessageBetweenLines(t *testing.T) {
+	text := "first line\nsecond line\nthird line"
+	parts := splitMessage(text, 25, telegramLength)
+	want := []string{"first line\nsecond line", "third line"}
+	if strings.Join(parts, "|") != strings.Join(want, "|") {
+		t.Errorf("splitMessage(%q) = %q, want %q", text, parts, want)
+	}
+}
+
+func TestSplitMessageCarriesFormatting(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		bold bool
+		code bool
+	}{
+		{"bold", "**" + strings.Repeat("bold words ", 20) + "**", true, false},
+		{"code", "`" + strings.Repeat("code words ", 20) + "`", false, true},
+		{"code in bold", "**`" + strings.Repeat("both words ", 20) + "`**", true, true},
+		{"bold lines", "**" + strings.Repeat("bold line\n", 20) + "**", true, false},
+	}
+	for _, tt := range tests {
+		for _, length := range []func(string) int{telegramLength, discordLength} {
+			parts := splitMessage(tt.text, 40, length)
+			if len(parts) < 2 {
+				t.Fatalf("%s: splitMessage returned %d part, want several", tt.name, len(parts))
+			}
+
+			var plain strings.Builder
+			for _, part := range parts {
+				if bold, code := formatting(part, false, false); bold || code {
+					t.Errorf("%s: part %q leaves formatting open", tt.name, part)
+				}
+				for _, s := range parseMarkdown(part) {
+					if strings.TrimSpace(s.text) != "" && (s.bold != tt.bold || s.code != tt.code) {
+						t.Errorf("%s: part %q has segment %+v, want bold=%v code=%v", tt.name, part, s, tt.bold, tt.code)
+					}
+				}
+				plain.WriteString(PlainText(part))
+			}
+
+			want := strings.ReplaceAll(PlainText(tt.text), "\n", "")
+			if got := strings.ReplaceAll(plain.String(), "\n", ""); got != want {
+				t.Errorf("%s: parts hold %q, want %q", tt.name, got, want)
+			}
+		}
+	}
+}
+
+func TestSplitMessageKeepsTokens(t *testing.T) {
+	// Every cut point of these lines falls inside an escape or a **, depending on the limit
+	texts := []string{
+		strings.Repeat(`a\*`, 30),
+		strings.Repeat(`a\\`, 30),
+		strings.Repeat("a**b**", 15),
+	}
+	for _, text := range texts {
+		for limit := 10; limit < 20; limit++ {
+			parts := splitMessage(text, limit, discordLength)
+			var plain strings.Builder
+			for _, part := range parts {
+				if length := discordLength(part); length > limit {
+					t.Errorf("part %q is %d long, want at most %d", part, length, limit)
+				}
+				plain.WriteString(PlainText(part))
+			}
+			if got, want := plain.String(), PlainText(text); got != want {
+				t.Errorf("splitMessage(%q, %d) holds %q, want %q", text, limit, got, want)
+			}
+		}
+	}
+}
+
+func TestSplitMessageTrailingAsterisk(t *testing.T) {
+	text := "**" + strings.Repeat("x", 30) + "*" + strings.Repeat("y", 30) + "**"
+	parts := splitMessage(text, 40, discordLength)
+	if !strings.HasSuffix(parts[0], `x\***`) {
+		t.Fatalf("first part %q should end with the escaped asterisk", parts[0])
+	}
+	var plain strings.Builder
+	for _, part := range parts {
+		plain.WriteString(PlainText(part))
+	}
+	if got, want := plain.String(), PlainText(text); got != want {
+		t.Errorf("parts %q hold %q, want %q", parts, got, want)
+	}
+}
+
+func TestSplitMessageLimit(t *testing.T) {
+	var text strings.Builder
+	for i := 0; i < 300; i++ {
+		text.WriteString("🥇 **" + Escape("Ann_*`") + "** - `12` solved\n")
+	}
+	tests := []struct {
+		name   string
+		limit  int
+		length func(string) int
+	}{
+		{"telegram", telegramMaxLength, telegramLength},
+		{"discord", discordMaxLength, discordLength},
+	}
+	for _, tt := range tests {
+		parts := splitMessage(text.String(), tt.limit, tt.length)
+		if len(parts) < 2 {
+			t.Errorf("%s: got %d part, want several", tt.name, len(parts))
+		}
+		for i, part := range parts {
+			if length := tt.length(part); length > tt.limit {
+				t.Errorf("%s: part %d is %d long, want at most %d", tt.name, i, length, tt.limit)
+			}
+		}
+	}
+}
+
+func TestTruncateMessage(t *testing.T) {
+	if got := truncateMessage("short", 10, discordLength); got != "short" {
+		t.Errorf("truncateMessage kept %q, want the message unchanged", got)
+	}
+
+	text := "**" + strings.Repeat("word ", 20) + "**"
+	got := truncateMessage(text, 30, discordLength)
+	if length := discordLength(got); length > 30 {
+		t.Errorf("truncateMessage(%q) = %q, %d long, want at most 30", text, got, length)
+	}
+	if !strings.HasSuffix(got, "**…") {
+		t.Errorf("truncateMessage(%q) = %q, want the bold closed before the ellipsis", text, got)
+	}
+}
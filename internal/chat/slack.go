@@ -0,0 +1,157 @@
+package chat
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// slackAPIURL is the base URL of the Slack Web API
+const slackAPIURL = "https://slack.com/api/"
+
+// slackMaxRequestAge is how old a slash command request may be, to stop replayed requests
+const slackMaxRequestAge = 5 * time.Minute
+
+// Slack is the Slack adapter. Messages are sent with the Web API and commands arrive as
+// slash commands, e.g. "/leetcode today", signed with the app's signing secret.
+type Slack struct {
+	token         string
+	signingSecret string
+	client        *http.Client
+}
+
+// NewSlack creates the Slack adapter from a bot token (xoxb-...) and the app's signing secret
+func NewSlack(token, signingSecret string) *Slack {
+	return &Slack{
+		token:         token,
+		signingSecret: signingSecret,
+		client:        &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns "slack"
+func (s *Slack) Name() string {
+	return PlatformSlack
+}
+
+// Send posts a message to a channel. Its ID is the message timestamp.
+func (s *Slack) Send(chatID, text string) (string, error) {
+	resp, err := s.call("chat.postMessage", map[string]string{"channel": chatID, "text": slackText(text)})
+	if err != nil {
+		return "", err
+	}
+	return resp.TS, nil
+}
+
+// Edit replaces the text of a message
+func (s *Slack) Edit(chatID, messageID, text string) error {
+	_, err := s.call("chat.update", map[string]string{"channel": chatID, "ts": messageID, "text": slackText(text)})
+	return err
+}
+
+// Mention mentions a user by ID
+func (s *Slack) Mention(user User) string {
+	return "<@" + user.ID + ">"
+}
+
+// slackResponse is the part of Web API responses the adapter uses
+type slackResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+	TS    string `json:"ts"`
+}
+
+// call calls a Web API method with a JSON body
+func (s *Slack) call(method string, params map[string]string) (*slackResponse, error) {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, slackAPIURL+method, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+s.token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("slack %s failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var result slackResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("slack %s returned %s: %w", method, resp.Status, err)
+	}
+	if !result.OK {
+		return nil, fmt.Errorf("slack %s failed: %s", method, result.Error)
+	}
+	return &result, nil
+}
+
+// CommandHandler serves the slash command endpoint, checking the request signature
+func (s *Slack) CommandHandler(handle CommandHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if !s.verify(r.Header, body) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		name, args := parseCommandText(form.Get("text"))
+		reply := handle(Command{
+			Platform: PlatformSlack,
+			ChatID:   form.Get("channel_id"),
+			User:     User{ID: form.Get("user_id"), Username: form.Get("user_name"), Name: form.Get("user_name")},
+			Name:     name,
+			Args:     args,
+		})
+
+		response := map[string]string{"response_type": "in_channel", "text": slackText(reply.Text)}
+		if reply.Private != "" {
+			response = map[string]string{"response_type": "ephemeral", "text": slackText(reply.Private)}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error answering Slack command: %v", err)
+		}
+	})
+}
+
+// verify checks the X-Slack-Signature header, the HMAC-SHA256 of "v0:<timestamp>:<body>"
+// keyed with the signing secret
+func (s *Slack) verify(header http.Header, body []byte) bool {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := time.Since(time.Unix(seconds, 0)); age > slackMaxRequestAge || age < -slackMaxRequestAge {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.signingSecret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature")))
+}
@@ -0,0 +1,51 @@
+package chat
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// slackSignature signs a request body the way Slack does
+func slackSignature(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestSlackVerify(t *testing.T) {
+	const body = "command=%2Fleetcode&text=today"
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-2*slackMaxRequestAge).Unix(), 10)
+	future := strconv.FormatInt(time.Now().Add(2*slackMaxRequestAge).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		body      string
+		want      bool
+	}{
+		{"valid", now, slackSignature("secret", now, body), body, true},
+		{"other secret", now, slackSignature("other", now, body), body, false},
+		{"tampered body", now, slackSignature("secret", now, body), body + "&x=1", false},
+		{"signed with another timestamp", now, slackSignature("secret", stale, body), body, false},
+		{"stale", stale, slackSignature("secret", stale, body), body, false},
+		{"from the future", future, slackSignature("secret", future, body), body, false},
+		{"no timestamp", "", slackSignature("secret", "", body), body, false},
+		{"no signature", now, "", body, false},
+	}
+	s := NewSlack("xoxb-test", "secret")
+	for _, tt := range tests {
+		header := http.Header{}
+		header.Set("X-Slack-Request-Timestamp", tt.timestamp)
+		header.Set("X-Slack-Signature", tt.signature)
+		if got := s.verify(header, []byte(tt.body)); got != tt.want {
+			t.Errorf("%s: verify = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package chat
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"leetcode-telegram-bot/internal/metrics"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// telegramMaxLength is the longest message text Telegram accepts
+const telegramMaxLength = 4096
+
+// Telegram is the Telegram adapter. Besides the Platform methods it can attach inline
+// keyboards, which the other platforms don't have.
+type Telegram struct {
+	api *tgbotapi.BotAPI
+}
+
+// NewTelegram creates the Telegram adapter
+func NewTelegram(api *tgbotapi.BotAPI) *Telegram {
+	return &Telegram{api: api}
+}
+
+// Name returns "telegram"
+func (t *Telegram) Name() string {
+	return PlatformTelegram
+}
+
+// Send posts a message to a chat
+func (t *Telegram) Send(chatID, text string) (string, error) {
+	id, err := strconv.ParseInt(chatID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid Telegram chat ID %q: %w", chatID, err)
+	}
+	messageID, err := t.SendWithKeyboard(id, text, nil)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(messageID), nil
+}
+
+// Edit replaces the text of a message
+func (t *Telegram) Edit(chatID, messageID, text string) error {
+	id, err := strconv.ParseInt(chatID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid Telegram chat ID %q: %w", chatID, err)
+	}
+	message, err := strconv.Atoi(messageID)
+	if err != nil {
+		return fmt.Errorf("invalid Telegram message ID %q: %w", messageID, err)
+	}
+	return t.EditWithKeyboard(id, message, text, nil)
+}
+
+// Mention mentions a user by @username, or by first name when they have none
+func (t *Telegram) Mention(user User) string {
+	if user.Username != "" {
+		return Escape("@" + user.Username)
+	}
+	return Escape(user.Name)
+}
+
+// SendWithKeyboard sends a message to a chat, with an optional inline keyboard, and returns its
+// message ID. Messages over Telegram's length limit are sent in parts, the keyboard going with
+// the last one, whose ID is returned.
+func (t *Telegram) SendWithKeyboard(chatID int64, text string, keyboard *tgbotapi.InlineKeyboardMarkup) (int, error) {
+	parts := splitMessage(text, telegramMaxLength, telegramLength)
+
+	var messageID int
+	for i, part := range parts {
+		last := i == len(parts)-1
+		sent, err := t.send(part, func(text, parseMode string) tgbotapi.Chattable {
+			msg := tgbotapi.NewMessage(chatID, text)
+			msg.ParseMode = parseMode
+			if last && keyboard != nil {
+				msg.ReplyMarkup = keyboard
+			}
+			return msg
+		})
+		if err != nil {
+			metrics.MessagesSent.Inc("error")
+			return 0, err
+		}
+		metrics.MessagesSent.Inc("ok")
+		messageID = sent.MessageID
+	}
+
+	return messageID, nil
+}
+
+// EditWithKeyboard replaces the text of a previously sent message. The inline keyboard
+// is replaced too, and removed when keyboard is nil. Text over the length limit is cut short.
+func (t *Telegram) EditWithKeyboard(chatID int64, messageID int, text string, keyboard *tgbotapi.InlineKeyboardMarkup) error {
+	_, err := t.send(truncateMessage(text, telegramMaxLength, telegramLength), func(text, parseMode string) tgbotapi.Chattable {
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+		edit.ParseMode = parseMode
+		edit.ReplyMarkup = keyboard
+		return edit
+	})
+	if err != nil && strings.Contains(err.Error(), "message is not modified") {
+		return nil
+	}
+	return err
+}
+
+// send sends a message written in the bot's Markdown, built by build from the converted text
+// and its parse mode. If Telegram can't parse the formatting, it is sent again as plain text.
+func (t *Telegram) send(text string, build func(text, parseMode string) tgbotapi.Chattable) (tgbotapi.Message, error) {
+	sent, err := t.api.Send(build(telegramHTML(text), tgbotapi.ModeHTML))
+	if err != nil && strings.Contains(err.Error(), "can't parse entities") {
+		log.Printf("Error formatting message, sending it as plain text: %v", err)
+		sent, err = t.api.Send(build(PlainText(text), ""))
+	}
+	return sent, err
+}
@@ -0,0 +1,144 @@
+package codeforces
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"leetcode-telegram-bot/internal/judge"
+)
+
+const apiEndpoint = "https://codeforces.com/api/"
+
+// Submission is a submission returned by the user.status method
+type Submission struct {
+	ID                  int64  `json:"id"`
+	ContestID           int    `json:"contestId"`
+	CreationTimeSeconds int64  `json:"creationTimeSeconds"`
+	Verdict             string `json:"verdict"`
+	Problem             struct {
+		ContestID int    `json:"contestId"`
+		Index     string `json:"index"`
+		Name      string `json:"name"`
+	} `json:"problem"`
+}
+
+// User is a user returned by the user.info method
+type User struct {
+	Handle       string `json:"handle"`
+	FirstName    string `json:"firstName"`
+	LastName     string `json:"lastName"`
+	Organization string `json:"organization"`
+	Rating       int    `json:"rating"`
+}
+
+type apiResponse struct {
+	Status  string          `json:"status"`
+	Comment string          `json:"comment"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// call calls a method of the Codeforces API and decodes its result into result
+func call(method string, params url.Values, result interface{}) error {
+	resp, err := http.Get(apiEndpoint + method + "?" + params.Encode())
+	if err != nil {
+		return fmt.Errorf("failed to call codeforces: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Failed calls answer 400 with the reason in the comment
+	var response apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed to decode response: %s", resp.Status)
+	}
+	if response.Status != "OK" {
+		return fmt.Errorf("codeforces %s failed: %s", method, response.Comment)
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("failed to decode result: %w", err)
+	}
+	return nil
+}
+
+// GetUserStatus fetches the latest submissions of a user
+func GetUserStatus(handle string, count int) ([]Submission, error) {
+	if handle == "" {
+		return nil, fmt.Errorf("handle cannot be empty")
+	}
+	params := url.Values{
+		"handle": {handle},
+		"from":   {"1"},
+		"count":  {strconv.Itoa(count)},
+	}
+	var submissions []Submission
+	if err := call("user.status", params, &submissions); err != nil {
+		return nil, fmt.Errorf("failed to get submissions of %s: %w", handle, err)
+	}
+	return submissions, nil
+}
+
+// GetUser fetches the public profile of a user
+func GetUser(handle string) (*User, error) {
+	if handle == "" {
+		return nil, fmt.Errorf("handle cannot be empty")
+	}
+	var users []User
+	if err := call("user.info", url.Values{"handles": {handle}}, &users); err != nil {
+		return nil, fmt.Errorf("failed to get user %s: %w", handle, err)
+	}
+	if len(users) == 0 {
+		return nil, fmt.Errorf("user %s not found", handle)
+	}
+	return &users[0], nil
+}
+
+// ProblemIDFromURL extracts the problem ID, e.g. "1850A", from a problem URL such as
+// https://codeforces.com/problemset/problem/1850/A or https://codeforces.com/contest/1850/problem/A
+func ProblemIDFromURL(problemURL string) string {
+	if judge.PlatformFromURL(problemURL) != judge.Codeforces {
+		return ""
+	}
+	return judge.ProblemSlug(problemURL)
+}
+
+// Checker verifies solves on Codeforces
+type Checker struct{}
+
+// ProblemID returns the ID of a problem, e.g. "1850A"
+func (Checker) ProblemID(problemURL string) string {
+	return ProblemIDFromURL(problemURL)
+}
+
+// RecentAccepted lists the recent accepted submissions of a Codeforces account
+func (Checker) RecentAccepted(account judge.Account) ([]judge.Accepted, error) {
+	submissions, err := GetUserStatus(account.Handle, 30)
+	if err != nil {
+		return nil, err
+	}
+
+	var accepted []judge.Accepted
+	for _, submission := range submissions {
+		if submission.Verdict != "OK" {
+			continue
+		}
+		accepted = append(accepted, judge.Accepted{
+			ProblemID: strconv.Itoa(submission.Problem.ContestID) + submission.Problem.Index,
+			Title:     submission.Problem.Name,
+			At:        time.Unix(submission.CreationTimeSeconds, 0),
+		})
+	}
+	return accepted, nil
+}
+
+// ProfileText returns the name and organization of a Codeforces account
+func (Checker) ProfileText(account judge.Account) (string, error) {
+	user, err := GetUser(account.Handle)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{user.FirstName, user.LastName, user.Organization}, "\n"), nil
+}
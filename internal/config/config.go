@@ -1,17 +1,66 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"regexp"
 	"strconv"
+	"time"
 )
 
+// webhookSecretPattern is the character set Telegram allows in a webhook secret token
+var webhookSecretPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
 // Config holds all configuration for the application
 type Config struct {
 	TelegramBotToken string
 	TelegramGroupID  int64
 	DatabasePath     string
 	ProblemsFilePath string
+	TracksFilePath   string
 	Timezone         string
+
+	// Time of day (HH:MM) after which shared solutions are posted to the group
+	SolutionRevealTime string
+
+	// Points deducted from a solve for each hint used on it, 0 turns the penalty off
+	HintPenalty float64
+
+	// Cron schedules of the end-of-day and end-of-week digests
+	DailyDigestSchedule  string
+	WeeklyDigestSchedule string
+
+	// Public HTTPS address Telegram sends updates to. Empty uses long polling instead.
+	WebhookURL string
+	// Secret checked both in the webhook path and in Telegram's secret token header
+	WebhookSecret string
+	// Whether the webhook is removed again on shutdown. Rolling updates turn it off, since
+	// by then the webhook already belongs to the new instance.
+	WebhookDeleteOnShutdown bool
+	// Address the health check, metrics and webhook server listens on
+	HTTPAddr string
+	// Whether the read-only web dashboard is served on /dashboard/. It has no login,
+	// so it is off unless turned on.
+	DashboardEnabled bool
+	// Bearer tokens of the JSON API on /api/v1/. The API is off while both are empty,
+	// and the admin endpoints only accept the admin token.
+	APIToken      string
+	APIAdminToken string
+
+	// Slack channel that runs the challenge program too. Empty token turns Slack off.
+	SlackBotToken      string
+	SlackSigningSecret string
+	SlackChannelID     string
+	// Discord channel that runs the challenge program too. Empty token turns Discord off.
+	DiscordBotToken      string
+	DiscordApplicationID string
+	DiscordPublicKey     string
+	DiscordChannelID     string
+
+	// Directory with message templates that override the built-in ones, as <language>/<name>.tmpl
+	TemplatesDir string
+	// Language of groups that haven't picked one with /language
+	DefaultLanguage string
 }
 
 // Load reads configuration from environment variables
@@ -21,7 +70,57 @@ func Load() (*Config, error) {
 		TelegramGroupID:  getEnvInt64("TELEGRAM_GROUP_ID", 0),
 		DatabasePath:     getEnv("DATABASE_PATH", "leetcode_bot.db"),
 		ProblemsFilePath: getEnv("PROBLEMS_FILE_PATH", "problem_deduplicated.yaml"),
+		TracksFilePath:   getEnv("TRACKS_FILE_PATH", "tracks.yaml"),
 		Timezone:         getEnv("TIMEZONE", "Asia/Ho_Chi_Minh"),
+
+		SolutionRevealTime: getEnv("SOLUTION_REVEAL_TIME", "21:00"),
+
+		HintPenalty: getEnvFloat64("HINT_PENALTY", 0),
+
+		DailyDigestSchedule:  getEnv("DAILY_DIGEST_SCHEDULE", "30 23 * * 1-5"),
+		WeeklyDigestSchedule: getEnv("WEEKLY_DIGEST_SCHEDULE", "0 10 * * 6"),
+
+		WebhookURL:              getEnv("WEBHOOK_URL", ""),
+		WebhookSecret:           getEnv("WEBHOOK_SECRET", ""),
+		WebhookDeleteOnShutdown: getEnvBool("WEBHOOK_DELETE_ON_SHUTDOWN", true),
+		HTTPAddr:                getEnv("HTTP_ADDR", ":8080"),
+
+		DashboardEnabled: getEnvBool("DASHBOARD_ENABLED", false),
+
+		APIToken:      getEnv("API_TOKEN", ""),
+		APIAdminToken: getEnv("API_ADMIN_TOKEN", ""),
+
+		SlackBotToken:      getEnv("SLACK_BOT_TOKEN", ""),
+		SlackSigningSecret: getEnv("SLACK_SIGNING_SECRET", ""),
+		SlackChannelID:     getEnv("SLACK_CHANNEL_ID", ""),
+
+		DiscordBotToken:      getEnv("DISCORD_BOT_TOKEN", ""),
+		DiscordApplicationID: getEnv("DISCORD_APPLICATION_ID", ""),
+		DiscordPublicKey:     getEnv("DISCORD_PUBLIC_KEY", ""),
+		DiscordChannelID:     getEnv("DISCORD_CHANNEL_ID", ""),
+
+		TemplatesDir:    getEnv("TEMPLATES_DIR", ""),
+		DefaultLanguage: getEnv("DEFAULT_LANGUAGE", "en"),
+	}
+
+	if _, err := time.Parse("15:04", cfg.SolutionRevealTime); err != nil {
+		return nil, fmt.Errorf("invalid SOLUTION_REVEAL_TIME %q, expected HH:MM: %w", cfg.SolutionRevealTime, err)
+	}
+
+	if cfg.HintPenalty < 0 || cfg.HintPenalty > 1 {
+		return nil, fmt.Errorf("invalid HINT_PENALTY %v, expected a value between 0 and 1", cfg.HintPenalty)
+	}
+
+	if cfg.WebhookURL != "" && !webhookSecretPattern.MatchString(cfg.WebhookSecret) {
+		return nil, fmt.Errorf("WEBHOOK_SECRET is required with WEBHOOK_URL and may only contain letters, digits, _ and -")
+	}
+
+	if cfg.SlackBotToken != "" && (cfg.SlackSigningSecret == "" || cfg.SlackChannelID == "") {
+		return nil, fmt.Errorf("SLACK_SIGNING_SECRET and SLACK_CHANNEL_ID are required with SLACK_BOT_TOKEN")
+	}
+
+	if cfg.DiscordBotToken != "" && (cfg.DiscordApplicationID == "" || cfg.DiscordPublicKey == "" || cfg.DiscordChannelID == "") {
+		return nil, fmt.Errorf("DISCORD_APPLICATION_ID, DISCORD_PUBLIC_KEY and DISCORD_CHANNEL_ID are required with DISCORD_BOT_TOKEN")
 	}
 
 	return cfg, nil
@@ -42,3 +141,21 @@ func getEnvInt64(key string, defaultValue int64) int64 {
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvFloat64(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
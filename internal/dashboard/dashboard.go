@@ -0,0 +1,252 @@
+package dashboard
+
+import (
+	"bytes"
+	"database/sql"
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"leetcode-telegram-bot/internal/achievements"
+	"leetcode-telegram-bot/internal/config"
+	"leetcode-telegram-bot/internal/database"
+	"leetcode-telegram-bot/internal/models"
+	"leetcode-telegram-bot/internal/server"
+)
+
+// files holds the templates and assets so the binary serves the dashboard on its own
+//
+//go:embed templates/*.html static/*
+var files embed.FS
+
+// basePath is where the dashboard is served
+const basePath = "/dashboard/"
+
+// windows are the time windows the leaderboard can be shown for, in days. Zero is all time.
+var windows = []struct {
+	Key   string
+	Label string
+	Days  int
+}{
+	{"week", "Last 7 days", 7},
+	{"month", "Last 30 days", 30},
+	{"all", "All time", 0},
+}
+
+// Dashboard is a read-only web UI over the bot's database
+type Dashboard struct {
+	db        *database.DB
+	config    *config.Config
+	location  *time.Location
+	templates map[string]*template.Template
+}
+
+// New creates the dashboard and parses its templates
+func New(db *database.DB, cfg *config.Config) (*Dashboard, error) {
+	funcs := template.FuncMap{
+		"add":      func(a, b int) int { return a + b },
+		"name":     fullName,
+		"duration": formatDuration,
+		"percent":  func(rate float64) string { return fmt.Sprintf("%.0f%%", rate*100) },
+		"badge": func(id string) string {
+			if achievement, ok := achievements.ByID(id); ok {
+				return achievement.Title()
+			}
+			return id
+		},
+	}
+
+	templates := make(map[string]*template.Template)
+	for _, page := range []string{"leaderboard.html", "history.html", "users.html", "user.html"} {
+		tmpl, err := template.New("layout.html").Funcs(funcs).ParseFS(files, "templates/layout.html", "templates/"+page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", page, err)
+		}
+		templates[page] = tmpl
+	}
+
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		log.Printf("Failed to load timezone %s, using UTC: %v", cfg.Timezone, err)
+		loc = time.UTC
+	}
+
+	return &Dashboard{db: db, config: cfg, location: loc, templates: templates}, nil
+}
+
+// now returns the current time in TIMEZONE, the timezone the bot dates challenges in
+func (d *Dashboard) now() time.Time {
+	return time.Now().In(d.location)
+}
+
+// RegisterHandlers adds the dashboard pages to the server
+func (d *Dashboard) RegisterHandlers(s *server.Server) {
+	static, err := fs.Sub(files, "static")
+	if err != nil {
+		log.Printf("Error loading dashboard assets: %v", err)
+	} else {
+		s.Handle(basePath+"static/", http.StripPrefix(basePath+"static/", http.FileServer(http.FS(static))))
+	}
+
+	s.Handle(basePath, http.HandlerFunc(d.handleLeaderboard))
+	s.Handle(basePath+"history", http.HandlerFunc(d.handleHistory))
+	s.Handle(basePath+"users", http.HandlerFunc(d.handleUsers))
+	s.Handle(basePath+"users/", http.HandlerFunc(d.handleUser))
+}
+
+// handleLeaderboard shows the leaderboard for the window picked with ?window=
+func (d *Dashboard) handleLeaderboard(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != basePath {
+		http.NotFound(w, r)
+		return
+	}
+
+	window := windows[0]
+	for _, candidate := range windows {
+		if candidate.Key == r.URL.Query().Get("window") {
+			window = candidate
+		}
+	}
+
+	var leaderboard []models.LeaderboardEntry
+	var err error
+	if window.Days == 0 {
+		leaderboard, err = d.db.GetLeaderboard(100, d.config.HintPenalty)
+	} else {
+		today := d.now()
+		from := today.AddDate(0, 0, -(window.Days - 1)).Format("2006-01-02")
+		leaderboard, err = d.db.GetLeaderboardBetween(from, today.Format("2006-01-02"), 100, d.config.HintPenalty)
+	}
+	if err != nil {
+		d.serverError(w, "leaderboard", err)
+		return
+	}
+
+	d.render(w, "leaderboard.html", map[string]interface{}{
+		"Title":       "Leaderboard",
+		"Windows":     windows,
+		"Window":      window.Key,
+		"Leaderboard": leaderboard,
+		"ShowPoints":  d.config.HintPenalty > 0,
+	})
+}
+
+// handleHistory lists past daily challenges by day number, latest first
+func (d *Dashboard) handleHistory(w http.ResponseWriter, r *http.Request) {
+	history, err := d.db.GetChallengeHistory("0000-01-01", d.now().Format("2006-01-02"))
+	if err != nil {
+		d.serverError(w, "challenge history", err)
+		return
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].Date > history[j].Date })
+
+	d.render(w, "history.html", map[string]interface{}{
+		"Title":   "History",
+		"History": history,
+	})
+}
+
+// handleUsers lists every member with their totals and streaks
+func (d *Dashboard) handleUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := d.db.GetAllUsers()
+	if err != nil {
+		d.serverError(w, "users", err)
+		return
+	}
+
+	today := d.now().Format("2006-01-02")
+	stats := make([]*models.UserStats, 0, len(users))
+	for _, user := range users {
+		userStats, err := d.db.GetUserStats(user.ID, today)
+		if err != nil {
+			log.Printf("Error getting stats for user %d: %v", user.ID, err)
+			continue
+		}
+		stats = append(stats, userStats)
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].TotalSolved != stats[j].TotalSolved {
+			return stats[i].TotalSolved > stats[j].TotalSolved
+		}
+		return stats[i].CurrentStreak > stats[j].CurrentStreak
+	})
+
+	d.render(w, "users.html", map[string]interface{}{
+		"Title": "Members",
+		"Stats": stats,
+	})
+}
+
+// handleUser shows the stats of one member
+func (d *Dashboard) handleUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, basePath+"users/"), 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	stats, err := d.db.GetUserStats(userID, d.now().Format("2006-01-02"))
+	if err == sql.ErrNoRows {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		d.serverError(w, "user stats", err)
+		return
+	}
+
+	categories := make([]string, 0, len(stats.ByCategory))
+	for category := range stats.ByCategory {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if stats.ByCategory[categories[i]] != stats.ByCategory[categories[j]] {
+			return stats.ByCategory[categories[i]] > stats.ByCategory[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
+
+	d.render(w, "user.html", map[string]interface{}{
+		"Title":      fullName(stats.User.FirstName, stats.User.LastName),
+		"Stats":      stats,
+		"Categories": categories,
+	})
+}
+
+// render executes a page template into a buffer first, so a failing template
+// doesn't send half a page
+func (d *Dashboard) render(w http.ResponseWriter, page string, data map[string]interface{}) {
+	var buf bytes.Buffer
+	if err := d.templates[page].ExecuteTemplate(&buf, "layout.html", data); err != nil {
+		d.serverError(w, "page "+page, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	buf.WriteTo(w)
+}
+
+func (d *Dashboard) serverError(w http.ResponseWriter, what string, err error) {
+	log.Printf("Error rendering dashboard %s: %v", what, err)
+	http.Error(w, "Something went wrong, please try again later.", http.StatusInternalServerError)
+}
+
+// fullName joins a first and last name
+func fullName(firstName, lastName string) string {
+	return strings.TrimSpace(firstName + " " + lastName)
+}
+
+// formatDuration formats a number of seconds as hours and minutes
+func formatDuration(seconds int64) string {
+	d := (time.Duration(seconds) * time.Second).Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -2,9 +2,13 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
+	"leetcode-telegram-bot/internal/judge"
 	"leetcode-telegram-bot/internal/models"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -35,6 +39,11 @@ func (db *DB) Close() error {
 	return db.conn.Close()
 }
 
+// Ping checks that the database can be reached
+func (db *DB) Ping() error {
+	return db.conn.Ping()
+}
+
 // createTables creates all necessary database tables
 func (db *DB) createTables() error {
 	queries := []string{
@@ -73,7 +82,7 @@ func (db *DB) createTables() error {
 		`CREATE TABLE IF NOT EXISTS user_leetcode_profiles (
 		    id INTEGER PRIMARY KEY AUTOINCREMENT,
 		    user_id INTEGER NOT NULL,
-		    leetcode_username TEXT NOT NULL UNIQUE,
+		    leetcode_username TEXT NOT NULL,
 		    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		    FOREIGN KEY (user_id) REFERENCES users (id)
 		)`,
@@ -82,6 +91,149 @@ func (db *DB) createTables() error {
 			current_day INTEGER NOT NULL DEFAULT 9,
 			last_updated DATETIME DEFAULT CURRENT_TIMESTAMP
 		)`,
+		`CREATE TABLE IF NOT EXISTS skipped_days (
+			date TEXT PRIMARY KEY,
+			skipped_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS reminder_opt_outs (
+			user_id INTEGER NOT NULL,
+			date TEXT NOT NULL,
+			PRIMARY KEY (user_id, date),
+			FOREIGN KEY (user_id) REFERENCES users (id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS solutions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			problem_id INTEGER NOT NULL,
+			date TEXT NOT NULL,
+			language TEXT NOT NULL,
+			code TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			revealed BOOLEAN NOT NULL DEFAULT FALSE,
+			FOREIGN KEY (user_id) REFERENCES users (id),
+			FOREIGN KEY (problem_id) REFERENCES problems (id),
+			UNIQUE(user_id, date, language)
+		)`,
+		`CREATE TABLE IF NOT EXISTS problem_hints (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			problem_id INTEGER NOT NULL,
+			text TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (problem_id) REFERENCES problems (id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS hint_usage (
+			user_id INTEGER NOT NULL,
+			problem_id INTEGER NOT NULL,
+			date TEXT NOT NULL,
+			level INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (user_id, problem_id, date),
+			FOREIGN KEY (user_id) REFERENCES users (id),
+			FOREIGN KEY (problem_id) REFERENCES problems (id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS user_achievements (
+			user_id INTEGER NOT NULL,
+			achievement_id TEXT NOT NULL,
+			unlocked_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, achievement_id),
+			FOREIGN KEY (user_id) REFERENCES users (id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS leetcode_profile_snapshots (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			leetcode_username TEXT NOT NULL,
+			easy_solved INTEGER NOT NULL DEFAULT 0,
+			medium_solved INTEGER NOT NULL DEFAULT 0,
+			hard_solved INTEGER NOT NULL DEFAULT 0,
+			total_solved INTEGER NOT NULL DEFAULT 0,
+			ranking INTEGER NOT NULL DEFAULT 0,
+			contest_rating REAL NOT NULL DEFAULT 0,
+			contests_attended INTEGER NOT NULL DEFAULT 0,
+			badges TEXT NOT NULL DEFAULT '[]',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users (id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS group_settings (
+			chat_id INTEGER NOT NULL,
+			key TEXT NOT NULL,
+			value TEXT NOT NULL,
+			PRIMARY KEY (chat_id, key)
+		)`,
+		`CREATE TABLE IF NOT EXISTS tracks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			start_date TEXT,
+			end_date TEXT
+		)`,
+		`CREATE TABLE IF NOT EXISTS track_problems (
+			track_id INTEGER NOT NULL,
+			problem_id INTEGER NOT NULL,
+			position INTEGER NOT NULL,
+			posted_date TEXT,
+			PRIMARY KEY (track_id, problem_id),
+			FOREIGN KEY (track_id) REFERENCES tracks (id),
+			FOREIGN KEY (problem_id) REFERENCES problems (id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS practice_problems (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			problem_id INTEGER NOT NULL,
+			assigned_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			solved_at DATETIME,
+			FOREIGN KEY (user_id) REFERENCES users (id),
+			FOREIGN KEY (problem_id) REFERENCES problems (id),
+			UNIQUE(user_id, problem_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS user_judge_accounts (
+			user_id INTEGER NOT NULL,
+			platform TEXT NOT NULL,
+			handle TEXT NOT NULL,
+			verified BOOLEAN NOT NULL DEFAULT FALSE,
+			verification_token TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, platform),
+			FOREIGN KEY (user_id) REFERENCES users (id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS challenge_queue (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			problem_id INTEGER NOT NULL UNIQUE,
+			scheduled_date TEXT UNIQUE,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (problem_id) REFERENCES problems (id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS outbound_webhooks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL,
+			events TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			webhook_id INTEGER NOT NULL,
+			event TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (webhook_id) REFERENCES outbound_webhooks (id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat_identities (
+			platform TEXT NOT NULL,
+			platform_user_id TEXT NOT NULL,
+			user_id INTEGER NOT NULL UNIQUE,
+			PRIMARY KEY (platform, platform_user_id),
+			FOREIGN KEY (user_id) REFERENCES users (id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS group_messages (
+			platform TEXT NOT NULL,
+			chat_id TEXT NOT NULL,
+			date TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			message_id TEXT NOT NULL,
+			PRIMARY KEY (platform, chat_id, date, kind)
+		)`,
 	}
 
 	for _, query := range queries {
@@ -96,134 +248,214 @@ func (db *DB) createTables() error {
 		return fmt.Errorf("failed to initialize challenge counter: %w", err)
 	}
 
-	return nil
+	return db.migrate()
 }
 
-// AddProblem adds a new problem to the database
-func (db *DB) AddProblem(problem *models.Problem) error {
-	query := `INSERT OR IGNORE INTO problems (title, url, category) VALUES (?, ?, ?)`
-	_, err := db.conn.Exec(query, problem.Title, problem.URL, problem.Category)
-	return err
-}
+// migrate adds columns introduced after the initial schema to existing databases
+func (db *DB) migrate() error {
+	columns := []struct {
+		table      string
+		column     string
+		definition string
+	}{
+		{"daily_challenges", "message_id", "INTEGER NOT NULL DEFAULT 0"},
+		{"problems", "difficulty", "TEXT NOT NULL DEFAULT ''"},
+		{"submissions", "verified", "BOOLEAN NOT NULL DEFAULT FALSE"},
+		{"daily_challenges", "board_message_id", "INTEGER NOT NULL DEFAULT 0"},
+		{"daily_challenges", "board_finalized", "BOOLEAN NOT NULL DEFAULT FALSE"},
+		{"submissions", "solved_at", "DATETIME"},
+		{"submissions", "solve_seconds", "INTEGER"},
+		{"user_leetcode_profiles", "verified", "BOOLEAN NOT NULL DEFAULT FALSE"},
+		{"user_leetcode_profiles", "verification_token", "TEXT NOT NULL DEFAULT ''"},
+		{"user_leetcode_profiles", "site", "TEXT NOT NULL DEFAULT 'leetcode.com'"},
+		{"problems", "platform", "TEXT NOT NULL DEFAULT 'leetcode'"},
+		{"problems", "slug", "TEXT NOT NULL DEFAULT ''"},
+	}
 
-// GetRandomUnusedProblem gets a random unused problem
-func (db *DB) GetRandomUnusedProblem() (*models.Problem, error) {
-	query := `SELECT id, title, url, category FROM problems WHERE used = FALSE ORDER BY RANDOM() LIMIT 1`
-	row := db.conn.QueryRow(query)
+	for _, c := range columns {
+		exists, err := db.columnExists(c.table, c.column)
+		if err != nil {
+			return fmt.Errorf("failed to inspect table %s: %w", c.table, err)
+		}
+		if exists {
+			continue
+		}
+		query := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, c.table, c.column, c.definition)
+		if _, err := db.conn.Exec(query); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", c.table, c.column, err)
+		}
+	}
 
-	var problem models.Problem
-	err := row.Scan(&problem.ID, &problem.Title, &problem.URL, &problem.Category)
-	if err != nil {
-		return nil, err
+	if err := db.migrateProblemSlugs(); err != nil {
+		return fmt.Errorf("failed to fill in problem slugs: %w", err)
 	}
 
-	return &problem, nil
-}
+	if err := db.migrateLeetcodeUsernameKey(); err != nil {
+		return fmt.Errorf("failed to migrate user_leetcode_profiles: %w", err)
+	}
 
-// MarkProblemAsUsed marks a problem as used
-func (db *DB) MarkProblemAsUsed(problemID int) error {
-	query := `UPDATE problems SET used = TRUE WHERE id = ?`
-	_, err := db.conn.Exec(query, problemID)
-	return err
-}
+	// Each user has at most one LeetCode profile, keep the latest one registered. A username
+	// is unique per LeetCode site, as leetcode.com and leetcode.cn accounts are unrelated.
+	profileMigrations := []string{
+		`DELETE FROM user_leetcode_profiles WHERE id NOT IN (SELECT MAX(id) FROM user_leetcode_profiles GROUP BY user_id)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_user_leetcode_profiles_user_id ON user_leetcode_profiles (user_id)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_user_leetcode_profiles_site_username ON user_leetcode_profiles (site, leetcode_username)`,
+	}
+	for _, query := range profileMigrations {
+		if _, err := db.conn.Exec(query); err != nil {
+			return fmt.Errorf("failed to migrate user_leetcode_profiles: %w", err)
+		}
+	}
 
-// AddUser adds or updates a user in the database
-func (db *DB) AddUser(user *models.User) error {
-	query := `INSERT OR REPLACE INTO users (id, username, first_name, last_name, created_at) 
-			  VALUES (?, ?, ?, ?, COALESCE((SELECT created_at FROM users WHERE id = ?), CURRENT_TIMESTAMP))`
-	_, err := db.conn.Exec(query, user.ID, user.Username, user.FirstName, user.LastName, user.ID)
-	return err
-}
+	// Links made before verification existed have no token. They stay self-reported until
+	// their owner proves the account with a fresh token, which /verify shows.
+	result, err := db.conn.Exec(`UPDATE user_leetcode_profiles SET verification_token = 'lcbot-' || lower(hex(randomblob(4)))
+			  WHERE verified = FALSE AND verification_token = ''`)
+	if err != nil {
+		return fmt.Errorf("failed to add verification tokens: %w", err)
+	}
+	if count, err := result.RowsAffected(); err == nil && count > 0 {
+		log.Printf("%d linked LeetCode accounts are unverified: their solves count as self-reported until they /verify", count)
+	}
 
-// AddSubmission adds a new submission
-func (db *DB) AddSubmission(submission *models.Submission) error {
-	query := `INSERT OR IGNORE INTO submissions (user_id, problem_id, date) VALUES (?, ?, ?)`
-	_, err := db.conn.Exec(query, submission.UserID, submission.ProblemID, submission.Date)
-	return err
+	return nil
 }
 
-// HasUserSubmittedToday checks if a user has submitted today
-func (db *DB) HasUserSubmittedToday(userID int64, date string) (bool, error) {
-	query := `SELECT COUNT(*) FROM submissions WHERE user_id = ? AND date = ?`
-	row := db.conn.QueryRow(query, userID, date)
-
-	var count int
-	err := row.Scan(&count)
+// migrateProblemSlugs fills in the slug of problems added before slugs were stored
+func (db *DB) migrateProblemSlugs() error {
+	rows, err := db.conn.Query(`SELECT id, url FROM problems WHERE slug = ''`)
 	if err != nil {
-		return false, err
+		return err
+	}
+	slugs := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var url string
+		if err := rows.Scan(&id, &url); err != nil {
+			rows.Close()
+			return err
+		}
+		if slug := judge.ProblemSlug(url); slug != "" {
+			slugs[id] = slug
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
 	}
 
-	return count > 0, nil
-}
-
-// AddDailyChallenge adds a new daily challenge
-func (db *DB) AddDailyChallenge(challenge *models.DailyChallenge) error {
-	query := `INSERT OR IGNORE INTO daily_challenges (problem_id, date, day_number) VALUES (?, ?, ?)`
-	_, err := db.conn.Exec(query, challenge.ProblemID, challenge.Date, challenge.DayNumber)
-	return err
+	for id, slug := range slugs {
+		if _, err := db.conn.Exec(`UPDATE problems SET slug = ? WHERE id = ?`, slug, id); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// GetCurrentDayNumber gets the current day number
-func (db *DB) GetCurrentDayNumber() (int, error) {
-	query := `SELECT current_day FROM challenge_counter WHERE id = 1`
-	row := db.conn.QueryRow(query)
-
-	var dayNumber int
-	err := row.Scan(&dayNumber)
+// migrateLeetcodeUsernameKey rebuilds user_leetcode_profiles of databases created when usernames
+// were unique across sites. SQLite can't drop a column constraint, so the rows are copied into
+// a new table without it.
+func (db *DB) migrateLeetcodeUsernameKey() error {
+	var definition string
+	err := db.conn.QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'user_leetcode_profiles'`).Scan(&definition)
 	if err != nil {
-		return 9, err // Default to day 9 if error
+		return err
+	}
+	if !strings.Contains(definition, "leetcode_username TEXT NOT NULL UNIQUE") {
+		return nil
 	}
 
-	return dayNumber, nil
-}
-
-// IncrementDayNumber increments the day number and returns the new value
-func (db *DB) IncrementDayNumber() (int, error) {
 	tx, err := db.conn.Begin()
 	if err != nil {
-		return 0, err
+		return err
 	}
 	defer tx.Rollback()
 
-	// Get current day number
-	var currentDay int
-	err = tx.QueryRow(`SELECT current_day FROM challenge_counter WHERE id = 1`).Scan(&currentDay)
-	if err != nil {
-		return 0, err
+	queries := []string{
+		`CREATE TABLE user_leetcode_profiles_new (
+		    id INTEGER PRIMARY KEY AUTOINCREMENT,
+		    user_id INTEGER NOT NULL,
+		    leetcode_username TEXT NOT NULL,
+		    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		    verified BOOLEAN NOT NULL DEFAULT FALSE,
+		    verification_token TEXT NOT NULL DEFAULT '',
+		    site TEXT NOT NULL DEFAULT 'leetcode.com',
+		    FOREIGN KEY (user_id) REFERENCES users (id)
+		)`,
+		`INSERT INTO user_leetcode_profiles_new (id, user_id, leetcode_username, created_at, verified, verification_token, site)
+		 SELECT id, user_id, leetcode_username, created_at, verified, verification_token, site FROM user_leetcode_profiles`,
+		`DROP TABLE user_leetcode_profiles`,
+		`ALTER TABLE user_leetcode_profiles_new RENAME TO user_leetcode_profiles`,
+	}
+	for _, query := range queries {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
 	}
 
-	// Increment and update
-	newDay := currentDay + 1
-	_, err = tx.Exec(`UPDATE challenge_counter SET current_day = ?, last_updated = CURRENT_TIMESTAMP WHERE id = 1`, newDay)
+	return tx.Commit()
+}
+
+// columnExists reports whether a table already has the given column
+func (db *DB) columnExists(table, column string) (bool, error) {
+	rows, err := db.conn.Query(fmt.Sprintf(`PRAGMA table_info(%s)`, table))
 	if err != nil {
-		return 0, err
+		return false, err
 	}
+	defer rows.Close()
 
-	err = tx.Commit()
-	if err != nil {
-		return 0, err
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			columnType string
+			notNull    int
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultVal, &primaryKey); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
 	}
 
-	return newDay, nil
+	return false, rows.Err()
 }
 
-// ResetDayNumber resets the day number back to 8 (so next challenge will be Day 9)
-func (db *DB) ResetDayNumber() error {
-	query := `UPDATE challenge_counter SET current_day = 8, last_updated = CURRENT_TIMESTAMP WHERE id = 1`
-	_, err := db.conn.Exec(query)
+// AddProblem adds a new problem to the database
+func (db *DB) AddProblem(problem *models.Problem) error {
+	platform, err := problemPlatform(problem.Platform, problem.URL)
+	if err != nil {
+		return err
+	}
+	query := `INSERT OR IGNORE INTO problems (title, url, category, platform, slug) VALUES (?, ?, ?, ?, ?)`
+	_, err = db.conn.Exec(query, problem.Title, problem.URL, problem.Category, platform, judge.ProblemSlug(problem.URL))
 	return err
 }
 
-// GetTodaysChallenge gets today's challenge
-func (db *DB) GetTodaysChallenge(date string) (*models.Problem, error) {
-	query := `SELECT p.id, p.title, p.url, p.category 
-			  FROM problems p 
-			  JOIN daily_challenges dc ON p.id = dc.problem_id 
-			  WHERE dc.date = ?`
+// problemPlatform returns the judge of a problem, detected from its URL when not given
+func problemPlatform(platform, url string) (judge.Platform, error) {
+	if platform == "" {
+		return judge.PlatformFromURL(url), nil
+	}
+	return judge.ParsePlatform(platform)
+}
+
+// GetRandomUnusedProblem gets a random unused problem that is neither planned in the queue
+// nor still to be posted by a track running or starting on or after the given date
+func (db *DB) GetRandomUnusedProblem(date string) (*models.Problem, error) {
+	query := `SELECT id, title, url, category, difficulty, platform FROM problems
+			  WHERE used = FALSE AND id NOT IN (SELECT problem_id FROM challenge_queue)
+			  AND id NOT IN (SELECT tp.problem_id FROM track_problems tp
+			                 JOIN tracks t ON t.id = tp.track_id
+			                 WHERE tp.posted_date IS NULL AND t.end_date >= ?)
+			  ORDER BY RANDOM() LIMIT 1`
 	row := db.conn.QueryRow(query, date)
 
 	var problem models.Problem
-	err := row.Scan(&problem.ID, &problem.Title, &problem.URL, &problem.Category)
+	err := row.Scan(&problem.ID, &problem.Title, &problem.URL, &problem.Category, &problem.Difficulty, &problem.Platform)
 	if err != nil {
 		return nil, err
 	}
@@ -231,130 +463,1923 @@ func (db *DB) GetTodaysChallenge(date string) (*models.Problem, error) {
 	return &problem, nil
 }
 
-// GetTodaysChallengeWithDay gets today's challenge with day number
-func (db *DB) GetTodaysChallengeWithDay(date string) (*models.Problem, int, error) {
-	query := `SELECT p.id, p.title, p.url, p.category, dc.day_number
-			  FROM problems p 
-			  JOIN daily_challenges dc ON p.id = dc.problem_id 
-			  WHERE dc.date = ?`
-	row := db.conn.QueryRow(query, date)
+// GetProblemBySlug gets a problem by the slug in its URL, e.g. "two-sum", or its
+// Codeforces problem ID, e.g. "1850A"
+func (db *DB) GetProblemBySlug(slug string) (*models.Problem, error) {
+	query := `SELECT id, title, url, category, difficulty, platform, used FROM problems
+			  WHERE slug = ? COLLATE NOCASE ORDER BY id LIMIT 1`
+	row := db.conn.QueryRow(query, slug)
 
 	var problem models.Problem
-	var dayNumber int
-	err := row.Scan(&problem.ID, &problem.Title, &problem.URL, &problem.Category, &dayNumber)
+	err := row.Scan(&problem.ID, &problem.Title, &problem.URL, &problem.Category, &problem.Difficulty, &problem.Platform, &problem.Used)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	return &problem, dayNumber, nil
+	return &problem, nil
 }
 
-// GetLeaderboard gets the leaderboard with user statistics
-func (db *DB) GetLeaderboard(limit int) ([]models.LeaderboardEntry, error) {
-	query := `SELECT u.id, u.username, u.first_name, u.last_name, COUNT(s.id) as total_solved
-			  FROM users u
-			  LEFT JOIN submissions s ON u.id = s.user_id
-			  GROUP BY u.id, u.username, u.first_name, u.last_name
-			  ORDER BY total_solved DESC, u.first_name ASC
-			  LIMIT ?`
+// GetProblemByTitle gets a problem by its title
+func (db *DB) GetProblemByTitle(title string) (*models.Problem, error) {
+	query := `SELECT id, title, url, category, difficulty, platform, used FROM problems WHERE title = ?`
+	row := db.conn.QueryRow(query, title)
 
-	rows, err := db.conn.Query(query, limit)
+	var problem models.Problem
+	err := row.Scan(&problem.ID, &problem.Title, &problem.URL, &problem.Category, &problem.Difficulty, &problem.Platform, &problem.Used)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var leaderboard []models.LeaderboardEntry
-	for rows.Next() {
-		var entry models.LeaderboardEntry
-		err := rows.Scan(&entry.UserID, &entry.Username, &entry.FirstName, &entry.LastName, &entry.TotalSolved)
-		if err != nil {
-			return nil, err
-		}
-		leaderboard = append(leaderboard, entry)
-	}
 
-	return leaderboard, nil
+	return &problem, nil
 }
 
-// GetUsersWhoDidntSubmitToday gets users who haven't submitted today
-func (db *DB) GetUsersWhoDidntSubmitToday(date string) ([]models.User, error) {
-	query := `SELECT u.id, u.username, u.first_name, u.last_name
-			  FROM users u
-			  WHERE u.id NOT IN (
-				  SELECT DISTINCT user_id FROM submissions WHERE date = ?
-			  )`
+// UnknownDifficulty is stored as the difficulty of problems LeetCode has no metadata for, so the
+// sync doesn't ask for them again
+const UnknownDifficulty = "?"
 
-	rows, err := db.conn.Query(query, date)
+// GetProblemsMissingDifficulty gets LeetCode problems whose difficulty has not been synced yet
+func (db *DB) GetProblemsMissingDifficulty(limit int) ([]models.Problem, error) {
+	query := `SELECT id, title, url, category FROM problems WHERE difficulty = '' AND platform = 'leetcode' ORDER BY id LIMIT ?`
+
+	rows, err := db.conn.Query(query, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var users []models.User
+	var problems []models.Problem
 	for rows.Next() {
-		var user models.User
-		err := rows.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName)
+		var problem models.Problem
+		err := rows.Scan(&problem.ID, &problem.Title, &problem.URL, &problem.Category)
 		if err != nil {
 			return nil, err
 		}
-		users = append(users, user)
+		problems = append(problems, problem)
 	}
 
-	return users, nil
+	return problems, nil
 }
 
-// LoadProblemsFromYAML loads problems from YAML file into database
-func (db *DB) LoadProblemsFromYAML(problems models.ProblemsData) error {
-	tx, err := db.conn.Begin()
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
-	for category, problemList := range problems {
-		for _, problem := range problemList {
-			_, err := tx.Exec(
-				`INSERT OR IGNORE INTO problems (title, url, category) VALUES (?, ?, ?)`,
-				problem.Title, problem.URL, category,
-			)
-			if err != nil {
-				log.Printf("Error inserting problem %s: %v", problem.Title, err)
-				continue
-			}
-		}
-	}
+// SetProblemDifficulty stores the difficulty of a problem
+func (db *DB) SetProblemDifficulty(problemID int, difficulty string) error {
+	query := `UPDATE problems SET difficulty = ? WHERE id = ?`
+	_, err := db.conn.Exec(query, difficulty, problemID)
+	return err
+}
 
-	return tx.Commit()
+// MarkProblemAsUsed marks a problem as used
+func (db *DB) MarkProblemAsUsed(problemID int) error {
+	query := `UPDATE problems SET used = TRUE WHERE id = ?`
+	_, err := db.conn.Exec(query, problemID)
+	return err
 }
 
-// GetLeetcodeProfile gets leetcode profile of user by id
+// MarkProblemAsUnused returns a problem to the pool of unused problems
+func (db *DB) MarkProblemAsUnused(problemID int) error {
+	query := `UPDATE problems SET used = FALSE WHERE id = ?`
+	_, err := db.conn.Exec(query, problemID)
+	return err
+}
+
+// AddUser adds or updates a user in the database
+func (db *DB) AddUser(user *models.User) error {
+	query := `INSERT OR REPLACE INTO users (id, username, first_name, last_name, created_at) 
+			  VALUES (?, ?, ?, ?, COALESCE((SELECT created_at FROM users WHERE id = ?), CURRENT_TIMESTAMP))`
+	_, err := db.conn.Exec(query, user.ID, user.Username, user.FirstName, user.LastName, user.ID)
+	return err
+}
+
+// GetUser gets a user by Telegram ID
+func (db *DB) GetUser(userID int64) (*models.User, error) {
+	query := `SELECT id, username, first_name, last_name, created_at FROM users WHERE id = ?`
+	return db.scanUser(db.conn.QueryRow(query, userID))
+}
+
+// GetOrCreateChatUser gets the member behind an account on a chat platform other than Telegram,
+// adding the member on first use and keeping their name up to date. These members get negative
+// IDs so they never clash with Telegram user IDs.
+func (db *DB) GetOrCreateChatUser(platform, platformUserID, username, firstName string) (*models.User, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var userID int64
+	err = tx.QueryRow(`SELECT user_id FROM chat_identities WHERE platform = ? AND platform_user_id = ?`,
+		platform, platformUserID).Scan(&userID)
+	if err == sql.ErrNoRows {
+		if err := tx.QueryRow(`SELECT MIN(0, COALESCE(MIN(id), 0)) - 1 FROM users`).Scan(&userID); err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(`INSERT INTO users (id, username, first_name, last_name) VALUES (?, ?, ?, '')`,
+			userID, username, firstName)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(`INSERT INTO chat_identities (platform, platform_user_id, user_id) VALUES (?, ?, ?)`,
+			platform, platformUserID, userID)
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	} else {
+		_, err = tx.Exec(`UPDATE users SET username = ?, first_name = ? WHERE id = ?`, username, firstName, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return db.GetUser(userID)
+}
+
+// GetChatIdentities gets the account IDs on a chat platform by user ID
+func (db *DB) GetChatIdentities(platform string) (map[int64]string, error) {
+	rows, err := db.conn.Query(`SELECT user_id, platform_user_id FROM chat_identities WHERE platform = ?`, platform)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := make(map[int64]string)
+	for rows.Next() {
+		var userID int64
+		var platformUserID string
+		if err := rows.Scan(&userID, &platformUserID); err != nil {
+			return nil, err
+		}
+		identities[userID] = platformUserID
+	}
+
+	return identities, rows.Err()
+}
+
+// GetUserByUsername gets a user by Telegram username, ignoring case
+func (db *DB) GetUserByUsername(username string) (*models.User, error) {
+	query := `SELECT id, username, first_name, last_name, created_at FROM users WHERE username = ? COLLATE NOCASE`
+	return db.scanUser(db.conn.QueryRow(query, username))
+}
+
+// scanUser scans a single user row including its creation time
+func (db *DB) scanUser(row *sql.Row) (*models.User, error) {
+	var user models.User
+	err := row.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName, &user.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// GetAllUsers gets all known users
+func (db *DB) GetAllUsers() ([]models.User, error) {
+	query := `SELECT u.id, u.username, u.first_name, u.last_name FROM users u ORDER BY u.first_name, u.id`
+	return db.queryUsers(query)
+}
+
+// AddSubmission adds a new submission. A self-reported submission that is
+// later verified keeps its original time and is marked as verified. When the
+// accepted time on LeetCode is known, the time to solve is measured from the post.
+func (db *DB) AddSubmission(submission *models.Submission) error {
+	var solvedAt interface{}
+	if submission.SolvedAt != nil {
+		solvedAt = submission.SolvedAt.UTC().Format("2006-01-02 15:04:05")
+	}
+
+	query := `INSERT INTO submissions (user_id, problem_id, date, verified, solved_at, solve_seconds)
+			  VALUES (?, ?, ?, ?, ?, (
+				  SELECT MAX(0, CAST(strftime('%s', ?) AS INTEGER) - CAST(strftime('%s', posted_at) AS INTEGER))
+				  FROM daily_challenges WHERE date = ?
+			  ))
+			  ON CONFLICT (user_id, problem_id, date) DO UPDATE SET
+				  verified = verified OR excluded.verified,
+				  solved_at = COALESCE(solved_at, excluded.solved_at),
+				  solve_seconds = COALESCE(solve_seconds, excluded.solve_seconds)`
+	_, err := db.conn.Exec(query, submission.UserID, submission.ProblemID, submission.Date, submission.Verified,
+		solvedAt, solvedAt, submission.Date)
+	return err
+}
+
+// GetSolvers gets the users who submitted on the given date, in order of submission
+func (db *DB) GetSolvers(date string) ([]models.User, error) {
+	query := `SELECT u.id, u.username, u.first_name, u.last_name
+			  FROM submissions s
+			  JOIN users u ON u.id = s.user_id
+			  WHERE s.date = ?
+			  ORDER BY s.submitted_at, s.id`
+
+	return db.queryUsers(query, date)
+}
+
+// HasUserSubmittedToday checks if a user has submitted today
+func (db *DB) HasUserSubmittedToday(userID int64, date string) (bool, error) {
+	query := `SELECT COUNT(*) FROM submissions WHERE user_id = ? AND date = ?`
+	row := db.conn.QueryRow(query, userID, date)
+
+	var count int
+	err := row.Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// HasVerifiedSubmission checks if a user has a verified submission on the given date
+func (db *DB) HasVerifiedSubmission(userID int64, date string) (bool, error) {
+	query := `SELECT COUNT(*) FROM submissions WHERE user_id = ? AND date = ? AND verified = TRUE`
+	row := db.conn.QueryRow(query, userID, date)
+
+	var count int
+	err := row.Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// AddDailyChallenge adds a new daily challenge
+func (db *DB) AddDailyChallenge(challenge *models.DailyChallenge) error {
+	query := `INSERT OR IGNORE INTO daily_challenges (problem_id, date, day_number) VALUES (?, ?, ?)`
+	_, err := db.conn.Exec(query, challenge.ProblemID, challenge.Date, challenge.DayNumber)
+	return err
+}
+
+// GetDailyChallenge gets the daily challenge posted on the given date
+func (db *DB) GetDailyChallenge(date string) (*models.DailyChallenge, error) {
+	query := `SELECT id, problem_id, date, posted_at, day_number, message_id, board_message_id, board_finalized
+			  FROM daily_challenges WHERE date = ?`
+	row := db.conn.QueryRow(query, date)
+
+	var challenge models.DailyChallenge
+	err := row.Scan(&challenge.ID, &challenge.ProblemID, &challenge.Date, &challenge.PostedAt, &challenge.DayNumber,
+		&challenge.MessageID, &challenge.BoardMessageID, &challenge.BoardFinalized)
+	if err != nil {
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+// SetDailyChallengeMessageID stores the Telegram message ID of a daily challenge announcement
+func (db *DB) SetDailyChallengeMessageID(date string, messageID int) error {
+	query := `UPDATE daily_challenges SET message_id = ? WHERE date = ?`
+	_, err := db.conn.Exec(query, messageID, date)
+	return err
+}
+
+// SetBoardMessageID stores the Telegram message ID of the progress board of a day
+func (db *DB) SetBoardMessageID(date string, messageID int) error {
+	query := `UPDATE daily_challenges SET board_message_id = ? WHERE date = ?`
+	_, err := db.conn.Exec(query, messageID, date)
+	return err
+}
+
+// MarkBoardFinalized records that the progress board of a day has been closed with its summary
+func (db *DB) MarkBoardFinalized(date string) error {
+	query := `UPDATE daily_challenges SET board_finalized = TRUE WHERE date = ?`
+	_, err := db.conn.Exec(query, date)
+	return err
+}
+
+// ReplaceDailyChallengeProblem swaps the problem of the daily challenge on the given date,
+// returning the old problem to the pool and keeping the same day number. Submissions, solutions
+// and hint usage of the old problem are dropped and the post time starts over.
+func (db *DB) ReplaceDailyChallengeProblem(date string, problemID int) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var oldProblemID int
+	err = tx.QueryRow(`SELECT problem_id FROM daily_challenges WHERE date = ?`, date).Scan(&oldProblemID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE problems SET used = FALSE WHERE id = ?`, oldProblemID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE problems SET used = TRUE WHERE id = ?`, problemID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM challenge_queue WHERE problem_id = ?`, problemID); err != nil {
+		return err
+	}
+	for _, table := range []string{"submissions", "solutions", "hint_usage"} {
+		if _, err := tx.Exec(`DELETE FROM `+table+` WHERE date = ?`, date); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(`UPDATE track_problems SET posted_date = NULL WHERE posted_date = ?`, date); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE daily_challenges SET problem_id = ?, posted_at = CURRENT_TIMESTAMP WHERE date = ?`, problemID, date); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SkipDay cancels the challenge on the given date. If a challenge was already posted,
+// its problem is returned to the pool, its submissions, solutions and hint usage are
+// dropped and the day counter is rolled back, so the skipped day leaves no trace in
+// streaks or day numbering.
+// It returns the cancelled challenge, or nil if nothing had been posted yet.
+func (db *DB) SkipDay(date string) (*models.DailyChallenge, error) {
+	challenge, err := db.GetDailyChallenge(date)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if challenge != nil {
+		if _, err := tx.Exec(`UPDATE problems SET used = FALSE WHERE id = ?`, challenge.ProblemID); err != nil {
+			return nil, err
+		}
+		for _, table := range []string{"submissions", "solutions", "hint_usage"} {
+			if _, err := tx.Exec(`DELETE FROM `+table+` WHERE date = ?`, date); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := tx.Exec(`DELETE FROM daily_challenges WHERE date = ?`, date); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(`UPDATE track_problems SET posted_date = NULL WHERE posted_date = ?`, date); err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(`UPDATE challenge_counter SET current_day = current_day - 1, last_updated = CURRENT_TIMESTAMP WHERE id = 1 AND current_day = ?`, challenge.DayNumber)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.Exec(`INSERT OR IGNORE INTO skipped_days (date) VALUES (?)`, date); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// IsDaySkipped checks if the challenge on the given date was cancelled
+func (db *DB) IsDaySkipped(date string) (bool, error) {
+	query := `SELECT COUNT(*) FROM skipped_days WHERE date = ?`
+	row := db.conn.QueryRow(query, date)
+
+	var count int
+	err := row.Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// GetDailyChallengeByDay gets the daily challenge with the given day number
+func (db *DB) GetDailyChallengeByDay(dayNumber int) (*models.DailyChallenge, error) {
+	query := `SELECT id, problem_id, date, posted_at, day_number, message_id, board_message_id, board_finalized
+			  FROM daily_challenges WHERE day_number = ? ORDER BY date DESC LIMIT 1`
+	row := db.conn.QueryRow(query, dayNumber)
+
+	var challenge models.DailyChallenge
+	err := row.Scan(&challenge.ID, &challenge.ProblemID, &challenge.Date, &challenge.PostedAt, &challenge.DayNumber,
+		&challenge.MessageID, &challenge.BoardMessageID, &challenge.BoardFinalized)
+	if err != nil {
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+// GetCurrentDayNumber gets the current day number
+func (db *DB) GetCurrentDayNumber() (int, error) {
+	query := `SELECT current_day FROM challenge_counter WHERE id = 1`
+	row := db.conn.QueryRow(query)
+
+	var dayNumber int
+	err := row.Scan(&dayNumber)
+	if err != nil {
+		return 9, err // Default to day 9 if error
+	}
+
+	return dayNumber, nil
+}
+
+// IncrementDayNumber increments the day number and returns the new value
+func (db *DB) IncrementDayNumber() (int, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Get current day number
+	var currentDay int
+	err = tx.QueryRow(`SELECT current_day FROM challenge_counter WHERE id = 1`).Scan(&currentDay)
+	if err != nil {
+		return 0, err
+	}
+
+	// Increment and update
+	newDay := currentDay + 1
+	_, err = tx.Exec(`UPDATE challenge_counter SET current_day = ?, last_updated = CURRENT_TIMESTAMP WHERE id = 1`, newDay)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	return newDay, nil
+}
+
+// ResetDayNumber resets the day number back to 8 (so next challenge will be Day 9)
+func (db *DB) ResetDayNumber() error {
+	query := `UPDATE challenge_counter SET current_day = 8, last_updated = CURRENT_TIMESTAMP WHERE id = 1`
+	_, err := db.conn.Exec(query)
+	return err
+}
+
+// GetTodaysChallenge gets today's challenge
+func (db *DB) GetTodaysChallenge(date string) (*models.Problem, error) {
+	query := `SELECT p.id, p.title, p.url, p.category, p.platform
+			  FROM problems p 
+			  JOIN daily_challenges dc ON p.id = dc.problem_id 
+			  WHERE dc.date = ?`
+	row := db.conn.QueryRow(query, date)
+
+	var problem models.Problem
+	err := row.Scan(&problem.ID, &problem.Title, &problem.URL, &problem.Category, &problem.Platform)
+	if err != nil {
+		return nil, err
+	}
+
+	return &problem, nil
+}
+
+// GetTodaysChallengeWithDay gets today's challenge with day number
+func (db *DB) GetTodaysChallengeWithDay(date string) (*models.Problem, int, error) {
+	query := `SELECT p.id, p.title, p.url, p.category, p.platform, dc.day_number
+			  FROM problems p 
+			  JOIN daily_challenges dc ON p.id = dc.problem_id 
+			  WHERE dc.date = ?`
+	row := db.conn.QueryRow(query, date)
+
+	var problem models.Problem
+	var dayNumber int
+	err := row.Scan(&problem.ID, &problem.Title, &problem.URL, &problem.Category, &problem.Platform, &dayNumber)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &problem, dayNumber, nil
+}
+
+// GetLeaderboard gets the leaderboard with user statistics. Every solve is worth one point,
+// minus hintPenalty for each hint used on it (never below zero).
+func (db *DB) GetLeaderboard(limit int, hintPenalty float64) ([]models.LeaderboardEntry, error) {
+	query := `SELECT u.id, u.username, u.first_name, u.last_name, COUNT(s.id) as total_solved,
+			  COALESCE(SUM(CASE WHEN s.id IS NULL THEN 0 ELSE MAX(0, 1 - ? * COALESCE(h.level, 0)) END), 0) as points
+			  FROM users u
+			  LEFT JOIN submissions s ON u.id = s.user_id
+			  LEFT JOIN hint_usage h ON h.user_id = s.user_id AND h.problem_id = s.problem_id AND h.date = s.date
+			  GROUP BY u.id, u.username, u.first_name, u.last_name
+			  ORDER BY points DESC, total_solved DESC, u.first_name ASC
+			  LIMIT ?`
+
+	rows, err := db.conn.Query(query, hintPenalty, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var leaderboard []models.LeaderboardEntry
+	for rows.Next() {
+		var entry models.LeaderboardEntry
+		err := rows.Scan(&entry.UserID, &entry.Username, &entry.FirstName, &entry.LastName, &entry.TotalSolved, &entry.Points)
+		if err != nil {
+			return nil, err
+		}
+		leaderboard = append(leaderboard, entry)
+	}
+
+	return leaderboard, nil
+}
+
+// GetLeaderboardBetween gets the leaderboard for submissions between two dates (inclusive),
+// listing only users who solved at least one challenge in that window. Points are counted
+// the same way as in GetLeaderboard.
+func (db *DB) GetLeaderboardBetween(fromDate, toDate string, limit int, hintPenalty float64) ([]models.LeaderboardEntry, error) {
+	query := `SELECT u.id, u.username, u.first_name, u.last_name, COUNT(s.id) as total_solved,
+			  SUM(MAX(0, 1 - ? * COALESCE(h.level, 0))) as points
+			  FROM users u
+			  JOIN submissions s ON u.id = s.user_id
+			  LEFT JOIN hint_usage h ON h.user_id = s.user_id AND h.problem_id = s.problem_id AND h.date = s.date
+			  WHERE s.date >= ? AND s.date <= ?
+			  GROUP BY u.id, u.username, u.first_name, u.last_name
+			  ORDER BY points DESC, total_solved DESC, u.first_name ASC
+			  LIMIT ?`
+
+	rows, err := db.conn.Query(query, hintPenalty, fromDate, toDate, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var leaderboard []models.LeaderboardEntry
+	for rows.Next() {
+		var entry models.LeaderboardEntry
+		err := rows.Scan(&entry.UserID, &entry.Username, &entry.FirstName, &entry.LastName, &entry.TotalSolved, &entry.Points)
+		if err != nil {
+			return nil, err
+		}
+		leaderboard = append(leaderboard, entry)
+	}
+
+	return leaderboard, nil
+}
+
+// GetSpeedLeaderboard gets the users with the lowest average time from post to accepted
+// submission, counting only verified solves with a known accepted time
+func (db *DB) GetSpeedLeaderboard(minSolves, limit int) ([]models.SpeedEntry, error) {
+	query := `SELECT u.id, u.username, u.first_name, u.last_name, COUNT(*) as solves,
+			  AVG(s.solve_seconds) as average_seconds, MIN(s.solve_seconds) as best_seconds
+			  FROM submissions s
+			  JOIN users u ON u.id = s.user_id
+			  WHERE s.verified = TRUE AND s.solve_seconds IS NOT NULL
+			  GROUP BY u.id, u.username, u.first_name, u.last_name
+			  HAVING COUNT(*) >= ?
+			  ORDER BY average_seconds ASC, solves DESC
+			  LIMIT ?`
+
+	rows, err := db.conn.Query(query, minSolves, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var leaderboard []models.SpeedEntry
+	for rows.Next() {
+		var entry models.SpeedEntry
+		var average float64
+		err := rows.Scan(&entry.UserID, &entry.Username, &entry.FirstName, &entry.LastName, &entry.Solves, &average, &entry.BestSeconds)
+		if err != nil {
+			return nil, err
+		}
+		entry.AverageSeconds = int64(average)
+		leaderboard = append(leaderboard, entry)
+	}
+
+	return leaderboard, nil
+}
+
+// GetChallengeHistory gets the daily challenges posted between two dates (inclusive),
+// oldest first, with the number of users who solved each of them
+func (db *DB) GetChallengeHistory(fromDate, toDate string) ([]models.ChallengeHistoryEntry, error) {
+	query := `SELECT dc.day_number, dc.date, dc.posted_at, p.id, p.title, p.url, p.category,
+			  (SELECT COUNT(*) FROM submissions s WHERE s.date = dc.date) as solvers
+			  FROM daily_challenges dc
+			  JOIN problems p ON p.id = dc.problem_id
+			  WHERE dc.date >= ? AND dc.date <= ?
+			  ORDER BY dc.date`
+
+	rows, err := db.conn.Query(query, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []models.ChallengeHistoryEntry
+	for rows.Next() {
+		var entry models.ChallengeHistoryEntry
+		err := rows.Scan(&entry.DayNumber, &entry.Date, &entry.PostedAt, &entry.ProblemID, &entry.Title,
+			&entry.URL, &entry.Category, &entry.Solvers)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, entry)
+	}
+
+	return history, nil
+}
+
+// GetUsersWhoDidntSubmitToday gets users who haven't submitted today
+func (db *DB) GetUsersWhoDidntSubmitToday(date string) ([]models.User, error) {
+	query := `SELECT u.id, u.username, u.first_name, u.last_name
+			  FROM users u
+			  WHERE u.id NOT IN (
+				  SELECT DISTINCT user_id FROM submissions WHERE date = ?
+			  )`
+
+	rows, err := db.conn.Query(query, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// GetVerifiedSolves gets the verified submissions of the given date, in order of submission
+func (db *DB) GetVerifiedSolves(date string) ([]models.SolveEntry, error) {
+	return db.querySolves(`s.date = ? AND s.verified = TRUE`, date)
+}
+
+// GetSolves gets all submissions of the given date, verified or self-reported, in order of submission
+func (db *DB) GetSolves(date string) ([]models.SolveEntry, error) {
+	return db.querySolves(`s.date = ?`, date)
+}
+
+// querySolves gets submissions matching the given condition, in order of submission
+func (db *DB) querySolves(condition string, args ...interface{}) ([]models.SolveEntry, error) {
+	query := `SELECT u.id, u.username, u.first_name, u.last_name, s.submitted_at, s.solved_at, s.solve_seconds, s.verified
+			  FROM submissions s
+			  JOIN users u ON u.id = s.user_id
+			  WHERE ` + condition + `
+			  ORDER BY COALESCE(s.solved_at, s.submitted_at), s.id`
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var solves []models.SolveEntry
+	for rows.Next() {
+		var solve models.SolveEntry
+		var solvedAt sql.NullTime
+		var solveSeconds sql.NullInt64
+		err := rows.Scan(&solve.UserID, &solve.Username, &solve.FirstName, &solve.LastName, &solve.SolvedAt,
+			&solvedAt, &solveSeconds, &solve.Verified)
+		if err != nil {
+			return nil, err
+		}
+		if solvedAt.Valid {
+			solve.SolvedAt = solvedAt.Time
+		}
+		if solveSeconds.Valid {
+			solve.SolveSeconds = &solveSeconds.Int64
+		}
+		solves = append(solves, solve)
+	}
+
+	return solves, nil
+}
+
+// CountUsers counts all known users
+func (db *DB) CountUsers() (int, error) {
+	var count int
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&count)
+	return count, err
+}
+
+// GetUsersWithoutVerifiedSubmission gets users whose submission on the given date
+// has not been verified yet, including users who only self-reported
+func (db *DB) GetUsersWithoutVerifiedSubmission(date string) ([]models.User, error) {
+	query := `SELECT u.id, u.username, u.first_name, u.last_name
+			  FROM users u
+			  WHERE u.id NOT IN (
+				  SELECT DISTINCT user_id FROM submissions WHERE date = ? AND verified = TRUE
+			  )`
+
+	return db.queryUsers(query, date)
+}
+
+// GetUsersToRemind gets users who haven't submitted on the given date and didn't skip it
+func (db *DB) GetUsersToRemind(date string) ([]models.User, error) {
+	query := `SELECT u.id, u.username, u.first_name, u.last_name
+			  FROM users u
+			  WHERE u.id NOT IN (
+				  SELECT DISTINCT user_id FROM submissions WHERE date = ?
+			  )
+			  AND u.id NOT IN (
+				  SELECT user_id FROM reminder_opt_outs WHERE date = ?
+			  )`
+
+	return db.queryUsers(query, date, date)
+}
+
+// queryUsers runs a query returning id, username, first_name and last_name of users
+func (db *DB) queryUsers(query string, args ...interface{}) ([]models.User, error) {
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// AddReminderOptOut records that a user skips the challenge on the given date,
+// so they are not reminded about it
+func (db *DB) AddReminderOptOut(userID int64, date string) error {
+	query := `INSERT OR IGNORE INTO reminder_opt_outs (user_id, date) VALUES (?, ?)`
+	_, err := db.conn.Exec(query, userID, date)
+	return err
+}
+
+// LoadProblemsFromYAML loads problems from YAML file into database
+func (db *DB) LoadProblemsFromYAML(problems models.ProblemsData) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for category, problemList := range problems {
+		for _, problem := range problemList {
+			platform, err := problemPlatform(problem.Platform, problem.URL)
+			if err != nil {
+				log.Printf("Skipping problem %s: %v", problem.Title, err)
+				continue
+			}
+			_, err = tx.Exec(
+				`INSERT OR IGNORE INTO problems (title, url, category, platform, slug) VALUES (?, ?, ?, ?, ?)`,
+				problem.Title, problem.URL, category, platform, judge.ProblemSlug(problem.URL),
+			)
+			if err != nil {
+				log.Printf("Error inserting problem %s: %v", problem.Title, err)
+				continue
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetLeetcodeProfile gets leetcode profile of user by id
 func (db *DB) GetLeetcodeProfile(id int64) (*models.User, error) {
 	query := `SELECT u.id, u.leetcode_username
 			  FROM user_leetcode_profiles u
 			  WHERE u.user_id = ?`
 
-	rows, err := db.conn.Query(query, id)
+	rows, err := db.conn.Query(query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var user models.User
+		err := rows.Scan(&user.ID, &user.Username)
+		if err != nil {
+			return nil, err
+		}
+		return &user, nil
+	}
+	return nil, fmt.Errorf("no leetcode profile found for user with id %d", id)
+}
+
+// GetLeetcodeLink gets a user's leetcode profile link with its site and verification state
+func (db *DB) GetLeetcodeLink(userID int64) (*models.UserLeetcodeProfile, error) {
+	query := `SELECT id, user_id, leetcode_username, site, created_at, verified, verification_token
+			  FROM user_leetcode_profiles WHERE user_id = ?`
+
+	var link models.UserLeetcodeProfile
+	err := db.conn.QueryRow(query, userID).Scan(&link.ID, &link.UserId, &link.LeetCodeUsername, &link.Site, &link.CreatedAt,
+		&link.Verified, &link.VerificationToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+// RegisterLeetcodeProfile registers an unverified leetcode profile for a user with the token
+// they have to put on their LeetCode profile, replacing the profile they had before.
+// Unverified claims of the same username on the same site by other users and profile
+// snapshots of the previous username are removed.
+func (db *DB) RegisterLeetcodeProfile(userID int64, leetcodeUsername, site, token string) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `DELETE FROM user_leetcode_profiles
+			  WHERE site = ? AND leetcode_username = ? COLLATE NOCASE AND user_id != ? AND verified = FALSE`
+	if _, err := tx.Exec(query, site, leetcodeUsername, userID); err != nil {
+		return err
+	}
+
+	query = `INSERT INTO user_leetcode_profiles (user_id, leetcode_username, site, verification_token) VALUES (?, ?, ?, ?)
+			  ON CONFLICT (user_id) DO UPDATE SET leetcode_username = excluded.leetcode_username, site = excluded.site,
+			  verification_token = excluded.verification_token, verified = FALSE, created_at = CURRENT_TIMESTAMP`
+	if _, err := tx.Exec(query, userID, leetcodeUsername, site, token); err != nil {
+		return err
+	}
+
+	query = `DELETE FROM leetcode_profile_snapshots WHERE user_id = ? AND leetcode_username != ?`
+	if _, err := tx.Exec(query, userID, leetcodeUsername); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MarkLeetcodeProfileVerified marks a user's leetcode profile link as verified
+func (db *DB) MarkLeetcodeProfileVerified(userID int64) error {
+	query := `UPDATE user_leetcode_profiles SET verified = TRUE, verification_token = '' WHERE user_id = ?`
+	_, err := db.conn.Exec(query, userID)
+	return err
+}
+
+// GetJudgeAccount gets a user's account on a judge other than LeetCode
+func (db *DB) GetJudgeAccount(userID int64, platform string) (*models.JudgeAccount, error) {
+	query := `SELECT user_id, platform, handle, verified, verification_token, created_at
+			  FROM user_judge_accounts WHERE user_id = ? AND platform = ?`
+
+	var account models.JudgeAccount
+	err := db.conn.QueryRow(query, userID, platform).Scan(&account.UserID, &account.Platform, &account.Handle,
+		&account.Verified, &account.VerificationToken, &account.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// LinkJudgeAccount links an unverified account on a judge to a user with the token they
+// have to put on their profile, replacing the account they had on that judge before
+func (db *DB) LinkJudgeAccount(userID int64, platform, handle, token string) error {
+	query := `INSERT INTO user_judge_accounts (user_id, platform, handle, verification_token) VALUES (?, ?, ?, ?)
+			  ON CONFLICT (user_id, platform) DO UPDATE SET handle = excluded.handle, verified = FALSE,
+			  verification_token = excluded.verification_token, created_at = CURRENT_TIMESTAMP`
+	_, err := db.conn.Exec(query, userID, platform, handle, token)
+	return err
+}
+
+// MarkJudgeAccountVerified marks a user's account on a judge as verified
+func (db *DB) MarkJudgeAccountVerified(userID int64, platform string) error {
+	query := `UPDATE user_judge_accounts SET verified = TRUE, verification_token = '' WHERE user_id = ? AND platform = ?`
+	_, err := db.conn.Exec(query, userID, platform)
+	return err
+}
+
+// UnregisterLeetcodeProfile removes a user's leetcode profile and its snapshots.
+// It reports whether the user had a profile.
+func (db *DB) UnregisterLeetcodeProfile(userID int64) (bool, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM user_leetcode_profiles WHERE user_id = ?`, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM leetcode_profile_snapshots WHERE user_id = ?`, userID); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// GetLeetcodeProfileOwner gets the ID of the user who registered a leetcode username on a site,
+// ignoring case, and whether their link is verified. A verified owner comes first.
+func (db *DB) GetLeetcodeProfileOwner(site, leetcodeUsername string) (int64, bool, error) {
+	var userID int64
+	var verified bool
+	query := `SELECT user_id, verified FROM user_leetcode_profiles WHERE site = ? AND leetcode_username = ? COLLATE NOCASE
+			  ORDER BY verified DESC LIMIT 1`
+	err := db.conn.QueryRow(query, site, leetcodeUsername).Scan(&userID, &verified)
+	return userID, verified, err
+}
+
+// GetLeetcodeProfiles gets all registered LeetCode profiles
+func (db *DB) GetLeetcodeProfiles() ([]models.UserLeetcodeProfile, error) {
+	query := `SELECT id, user_id, leetcode_username, site, created_at FROM user_leetcode_profiles ORDER BY id`
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var profiles []models.UserLeetcodeProfile
+	for rows.Next() {
+		var profile models.UserLeetcodeProfile
+		err := rows.Scan(&profile.ID, &profile.UserId, &profile.LeetCodeUsername, &profile.Site, &profile.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, nil
+}
+
+// EnqueueProblem plans a problem ahead of time. An empty date queues it for the
+// next day that has nothing scheduled.
+func (db *DB) EnqueueProblem(problemID int, scheduledDate string) error {
+	var date interface{}
+	if scheduledDate != "" {
+		date = scheduledDate
+	}
+
+	query := `INSERT INTO challenge_queue (problem_id, scheduled_date) VALUES (?, ?)`
+	_, err := db.conn.Exec(query, problemID, date)
+	return err
+}
+
+// GetQueue gets all queued problems, dated entries first in date order, then undated ones in the order they were added
+func (db *DB) GetQueue() ([]models.QueueEntry, error) {
+	query := `SELECT q.id, q.problem_id, p.title, p.url, COALESCE(q.scheduled_date, ''), q.created_at
+			  FROM challenge_queue q
+			  JOIN problems p ON p.id = q.problem_id
+			  ORDER BY q.scheduled_date IS NULL, q.scheduled_date, q.id`
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var queue []models.QueueEntry
+	for rows.Next() {
+		var entry models.QueueEntry
+		err := rows.Scan(&entry.ID, &entry.ProblemID, &entry.Title, &entry.URL, &entry.ScheduledDate, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		queue = append(queue, entry)
+	}
+
+	return queue, nil
+}
+
+// GetNextQueuedProblem gets the queued problem to post on the given date: an entry
+// scheduled for that date (or a missed earlier one) first, then the oldest undated entry.
+// Entries of problems that were posted some other way in the meantime are dropped.
+func (db *DB) GetNextQueuedProblem(date string) (*models.Problem, error) {
+	_, err := db.conn.Exec(`DELETE FROM challenge_queue WHERE problem_id IN (SELECT id FROM problems WHERE used = TRUE)`)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT p.id, p.title, p.url, p.category, p.difficulty, p.platform
+			  FROM challenge_queue q
+			  JOIN problems p ON p.id = q.problem_id
+			  WHERE q.scheduled_date IS NULL OR q.scheduled_date <= ?
+			  ORDER BY q.scheduled_date IS NULL, q.scheduled_date, q.id
+			  LIMIT 1`
+	row := db.conn.QueryRow(query, date)
+
+	var problem models.Problem
+	err = row.Scan(&problem.ID, &problem.Title, &problem.URL, &problem.Category, &problem.Difficulty, &problem.Platform)
+	if err != nil {
+		return nil, err
+	}
+
+	return &problem, nil
+}
+
+// IsProblemQueued checks if a problem is already in the queue
+func (db *DB) IsProblemQueued(problemID int) (bool, error) {
+	query := `SELECT COUNT(*) FROM challenge_queue WHERE problem_id = ?`
+	row := db.conn.QueryRow(query, problemID)
+
+	var count int
+	err := row.Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// RemoveQueueEntry removes an entry from the queue by its ID, reporting whether it existed
+func (db *DB) RemoveQueueEntry(id int) (bool, error) {
+	result, err := db.conn.Exec(`DELETE FROM challenge_queue WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// DequeueProblem removes a problem from the queue, e.g. once it has been posted
+func (db *DB) DequeueProblem(problemID int) error {
+	_, err := db.conn.Exec(`DELETE FROM challenge_queue WHERE problem_id = ?`, problemID)
+	return err
+}
+
+// LoadTracksFromYAML loads curated tracks into the database. Problems listed in a track
+// are added to the problem list if missing. Dates from the file override the stored
+// range only when present, so ranges set with /track survive restarts otherwise.
+func (db *DB) LoadTracksFromYAML(tracks models.TracksData) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, track := range tracks {
+		if track.Name == "" {
+			log.Printf("Skipping track without a name")
+			continue
+		}
+
+		_, err := tx.Exec(`INSERT OR IGNORE INTO tracks (name) VALUES (?)`, track.Name)
+		if err != nil {
+			return fmt.Errorf("failed to insert track %s: %w", track.Name, err)
+		}
+		if track.Start != "" && track.End != "" {
+			_, err := tx.Exec(`UPDATE tracks SET start_date = ?, end_date = ? WHERE name = ?`, track.Start, track.End, track.Name)
+			if err != nil {
+				return fmt.Errorf("failed to set dates of track %s: %w", track.Name, err)
+			}
+		}
+
+		var trackID int
+		if err := tx.QueryRow(`SELECT id FROM tracks WHERE name = ?`, track.Name).Scan(&trackID); err != nil {
+			return err
+		}
+
+		var problemIDs []interface{}
+		for position, problem := range track.Problems {
+			category := problem.Category
+			if category == "" {
+				category = track.Name
+			}
+			platform, err := problemPlatform(problem.Platform, problem.URL)
+			if err != nil {
+				log.Printf("Skipping problem %s: %v", problem.Title, err)
+				continue
+			}
+			_, err = tx.Exec(
+				`INSERT OR IGNORE INTO problems (title, url, category, platform, slug) VALUES (?, ?, ?, ?, ?)`,
+				problem.Title, problem.URL, category, platform, judge.ProblemSlug(problem.URL),
+			)
+			if err != nil {
+				log.Printf("Error inserting problem %s: %v", problem.Title, err)
+				continue
+			}
+
+			var problemID int
+			if err := tx.QueryRow(`SELECT id FROM problems WHERE title = ?`, problem.Title).Scan(&problemID); err != nil {
+				log.Printf("Error finding problem %s: %v", problem.Title, err)
+				continue
+			}
+
+			_, err = tx.Exec(
+				`INSERT INTO track_problems (track_id, problem_id, position) VALUES (?, ?, ?)
+				 ON CONFLICT (track_id, problem_id) DO UPDATE SET position = excluded.position`,
+				trackID, problemID, position,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to add %s to track %s: %w", problem.Title, track.Name, err)
+			}
+			problemIDs = append(problemIDs, problemID)
+		}
+
+		// Drop problems removed from the file, unless they were already posted
+		query := `DELETE FROM track_problems WHERE track_id = ? AND posted_date IS NULL`
+		args := []interface{}{trackID}
+		if len(problemIDs) > 0 {
+			query += ` AND problem_id NOT IN (?` + strings.Repeat(`, ?`, len(problemIDs)-1) + `)`
+			args = append(args, problemIDs...)
+		}
+		if _, err := tx.Exec(query, args...); err != nil {
+			return fmt.Errorf("failed to prune track %s: %w", track.Name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// trackColumns selects a track together with its progress
+const trackColumns = `t.id, t.name, COALESCE(t.start_date, ''), COALESCE(t.end_date, ''),
+			  (SELECT COUNT(*) FROM track_problems tp WHERE tp.track_id = t.id),
+			  (SELECT COUNT(*) FROM track_problems tp WHERE tp.track_id = t.id AND tp.posted_date IS NOT NULL)`
+
+// GetTracks gets all tracks with their progress
+func (db *DB) GetTracks() ([]models.Track, error) {
+	query := `SELECT ` + trackColumns + ` FROM tracks t ORDER BY t.start_date IS NULL, t.start_date, t.name`
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tracks []models.Track
+	for rows.Next() {
+		var track models.Track
+		err := rows.Scan(&track.ID, &track.Name, &track.StartDate, &track.EndDate, &track.Total, &track.Posted)
+		if err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, track)
+	}
+
+	return tracks, nil
+}
+
+// GetActiveTrack gets the track running on the given date
+func (db *DB) GetActiveTrack(date string) (*models.Track, error) {
+	query := `SELECT ` + trackColumns + ` FROM tracks t
+			  WHERE t.start_date <= ? AND t.end_date >= ?
+			  ORDER BY t.start_date DESC LIMIT 1`
+	row := db.conn.QueryRow(query, date, date)
+
+	var track models.Track
+	err := row.Scan(&track.ID, &track.Name, &track.StartDate, &track.EndDate, &track.Total, &track.Posted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &track, nil
+}
+
+// SetTrackDates turns a track on for a date range, or off when both dates are empty
+func (db *DB) SetTrackDates(name, startDate, endDate string) (bool, error) {
+	var start, end interface{}
+	if startDate != "" && endDate != "" {
+		start, end = startDate, endDate
+	}
+
+	result, err := db.conn.Exec(`UPDATE tracks SET start_date = ?, end_date = ? WHERE name = ? COLLATE NOCASE`, start, end, name)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// GetNextTrackProblem gets the first problem of a track, in track order, that has not been posted
+// yet. Problems already used as a daily challenge some other way are passed over.
+func (db *DB) GetNextTrackProblem(trackID int) (*models.Problem, error) {
+	query := `SELECT p.id, p.title, p.url, p.category, p.difficulty, p.platform
+			  FROM track_problems tp
+			  JOIN problems p ON p.id = tp.problem_id
+			  WHERE tp.track_id = ? AND tp.posted_date IS NULL AND p.used = FALSE
+			  ORDER BY tp.position
+			  LIMIT 1`
+	row := db.conn.QueryRow(query, trackID)
+
+	var problem models.Problem
+	err := row.Scan(&problem.ID, &problem.Title, &problem.URL, &problem.Category, &problem.Difficulty, &problem.Platform)
+	if err != nil {
+		return nil, err
+	}
+
+	return &problem, nil
+}
+
+// MarkTrackProblemPosted records that a problem of the track active on the given date has been posted
+func (db *DB) MarkTrackProblemPosted(problemID int, date string) error {
+	query := `UPDATE track_problems SET posted_date = ?
+			  WHERE problem_id = ? AND posted_date IS NULL
+			  AND track_id IN (SELECT id FROM tracks WHERE start_date <= ? AND end_date >= ?)`
+	_, err := db.conn.Exec(query, date, problemID, date, date)
+	return err
+}
+
+// GetPracticeCandidates gets random past LeetCode daily challenges a user has never solved in the
+// group nor been given for practice, optionally filtered by category (substring match) and
+// difficulty. Problems that haven't been posted yet stay fresh for the daily challenge.
+func (db *DB) GetPracticeCandidates(userID int64, category, difficulty string, limit int) ([]models.Problem, error) {
+	query := `SELECT p.id, p.title, p.url, p.category, p.difficulty
+			  FROM problems p
+			  WHERE p.platform = 'leetcode' AND p.used = TRUE
+			  AND p.id NOT IN (SELECT problem_id FROM submissions WHERE user_id = ?)
+			  AND p.id NOT IN (SELECT problem_id FROM practice_problems WHERE user_id = ?)
+			  AND (? = '' OR p.category LIKE '%' || ? || '%')
+			  AND (? = '' OR p.difficulty = ? COLLATE NOCASE)
+			  ORDER BY RANDOM()
+			  LIMIT ?`
+
+	rows, err := db.conn.Query(query, userID, userID, category, category, difficulty, difficulty, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var problems []models.Problem
+	for rows.Next() {
+		var problem models.Problem
+		err := rows.Scan(&problem.ID, &problem.Title, &problem.URL, &problem.Category, &problem.Difficulty)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, problem)
+	}
+
+	return problems, nil
+}
+
+// AddPracticeProblem hands out a problem to a user for personal practice
+func (db *DB) AddPracticeProblem(userID int64, problemID int) error {
+	query := `INSERT OR IGNORE INTO practice_problems (user_id, problem_id) VALUES (?, ?)`
+	_, err := db.conn.Exec(query, userID, problemID)
+	return err
+}
+
+// GetOpenPracticeProblems gets the practice problems a user has not solved yet
+func (db *DB) GetOpenPracticeProblems(userID int64) ([]models.PracticeProblem, error) {
+	query := `SELECT pp.id, pp.user_id, pp.problem_id, p.title, p.url, p.category, p.difficulty, pp.assigned_at
+			  FROM practice_problems pp
+			  JOIN problems p ON p.id = pp.problem_id
+			  WHERE pp.user_id = ? AND pp.solved_at IS NULL
+			  ORDER BY pp.assigned_at`
+
+	rows, err := db.conn.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var problems []models.PracticeProblem
+	for rows.Next() {
+		var problem models.PracticeProblem
+		err := rows.Scan(&problem.ID, &problem.UserID, &problem.ProblemID, &problem.Title, &problem.URL,
+			&problem.Category, &problem.Difficulty, &problem.AssignedAt)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, problem)
+	}
+
+	return problems, nil
+}
+
+// GetUsersWithOpenPractice gets the IDs of users who have unsolved practice problems
+func (db *DB) GetUsersWithOpenPractice() ([]int64, error) {
+	query := `SELECT DISTINCT user_id FROM practice_problems WHERE solved_at IS NULL`
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, nil
+}
+
+// MarkPracticeSolved records when a practice problem was solved
+func (db *DB) MarkPracticeSolved(practiceID int, solvedAt time.Time) error {
+	query := `UPDATE practice_problems SET solved_at = ? WHERE id = ?`
+	_, err := db.conn.Exec(query, solvedAt, practiceID)
+	return err
+}
+
+// GetPracticeStats gets a user's personal practice statistics
+func (db *DB) GetPracticeStats(userID int64) (*models.PracticeStats, error) {
+	query := `SELECT p.category, p.difficulty, pp.solved_at IS NOT NULL
+			  FROM practice_problems pp
+			  JOIN problems p ON p.id = pp.problem_id
+			  WHERE pp.user_id = ?`
+
+	rows, err := db.conn.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := &models.PracticeStats{
+		ByDifficulty: make(map[string]int),
+		ByCategory:   make(map[string]int),
+	}
+	for rows.Next() {
+		var category, difficulty string
+		var solved bool
+		if err := rows.Scan(&category, &difficulty, &solved); err != nil {
+			return nil, err
+		}
+		stats.Assigned++
+		if !solved {
+			continue
+		}
+		stats.Solved++
+		if difficulty == "" {
+			difficulty = "Unknown"
+		}
+		stats.ByDifficulty[difficulty]++
+		stats.ByCategory[category]++
+	}
+
+	return stats, nil
+}
+
+// GetGroupSetting gets a per-group setting, or the default value when it has not been set
+func (db *DB) GetGroupSetting(chatID int64, key, defaultValue string) (string, error) {
+	var value string
+	err := db.conn.QueryRow(`SELECT value FROM group_settings WHERE chat_id = ? AND key = ?`, chatID, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return defaultValue, nil
+	}
+	if err != nil {
+		return defaultValue, err
+	}
+
+	return value, nil
+}
+
+// SetGroupSetting stores a per-group setting
+func (db *DB) SetGroupSetting(chatID int64, key, value string) error {
+	query := `INSERT OR REPLACE INTO group_settings (chat_id, key, value) VALUES (?, ?, ?)`
+	_, err := db.conn.Exec(query, chatID, key, value)
+	return err
+}
+
+// SaveSolution stores a user's solution for the challenge of the given date,
+// replacing an earlier one in the same language. A solution already revealed stays
+// revealed, so it is never posted to the group twice.
+func (db *DB) SaveSolution(solution *models.Solution) error {
+	query := `INSERT INTO solutions (user_id, problem_id, date, language, code) VALUES (?, ?, ?, ?, ?)
+			  ON CONFLICT (user_id, date, language) DO UPDATE SET code = excluded.code, created_at = CURRENT_TIMESTAMP`
+	_, err := db.conn.Exec(query, solution.UserID, solution.ProblemID, solution.Date, solution.Language, solution.Code)
+	return err
+}
+
+// GetSolutions gets the solutions shared for the challenge of the given date
+func (db *DB) GetSolutions(date string) ([]models.Solution, error) {
+	return db.querySolutions(`s.date = ?`, date)
+}
+
+// GetUnrevealedSolutions gets solutions of challenges up to the given date that haven't been posted to the group yet
+func (db *DB) GetUnrevealedSolutions(upToDate string) ([]models.Solution, error) {
+	return db.querySolutions(`s.date <= ? AND s.revealed = FALSE`, upToDate)
+}
+
+// querySolutions gets solutions matching the given condition, oldest first
+func (db *DB) querySolutions(condition string, args ...interface{}) ([]models.Solution, error) {
+	query := `SELECT s.id, s.user_id, u.first_name, s.problem_id, s.date, dc.day_number, s.language, s.code, s.created_at, s.revealed
+			  FROM solutions s
+			  JOIN users u ON u.id = s.user_id
+			  JOIN daily_challenges dc ON dc.date = s.date
+			  WHERE ` + condition + `
+			  ORDER BY s.created_at, s.id`
+
+	rows, err := db.conn.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	if rows.Next() {
-		var user models.User
-		err := rows.Scan(&user.ID, &user.Username)
+	var solutions []models.Solution
+	for rows.Next() {
+		var solution models.Solution
+		err := rows.Scan(&solution.ID, &solution.UserID, &solution.FirstName, &solution.ProblemID, &solution.Date,
+			&solution.DayNumber, &solution.Language, &solution.Code, &solution.CreatedAt, &solution.Revealed)
 		if err != nil {
 			return nil, err
 		}
-		return &user, nil
+		solutions = append(solutions, solution)
 	}
-	return nil, fmt.Errorf("no leetcode profile found for user with id %d", id)
+
+	return solutions, nil
+}
+
+// MarkSolutionRevealed records that a solution has been posted to the group
+func (db *DB) MarkSolutionRevealed(solutionID int) error {
+	query := `UPDATE solutions SET revealed = TRUE WHERE id = ?`
+	_, err := db.conn.Exec(query, solutionID)
+	return err
+}
+
+// AddProblemHint adds a hint to a problem, after the hints it already has
+func (db *DB) AddProblemHint(problemID int, text string) error {
+	query := `INSERT INTO problem_hints (problem_id, text) VALUES (?, ?)`
+	_, err := db.conn.Exec(query, problemID, text)
+	return err
+}
+
+// GetProblemHints gets the hints of a problem in the order they were added
+func (db *DB) GetProblemHints(problemID int) ([]string, error) {
+	query := `SELECT text FROM problem_hints WHERE problem_id = ? ORDER BY id`
+	rows, err := db.conn.Query(query, problemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hints []string
+	for rows.Next() {
+		var hint string
+		if err := rows.Scan(&hint); err != nil {
+			return nil, err
+		}
+		hints = append(hints, hint)
+	}
+
+	return hints, nil
+}
+
+// GetHintLevel gets how many hints a user has seen for a problem on the given date
+func (db *DB) GetHintLevel(userID int64, problemID int, date string) (int, error) {
+	var level int
+	query := `SELECT level FROM hint_usage WHERE user_id = ? AND problem_id = ? AND date = ?`
+	err := db.conn.QueryRow(query, userID, problemID, date).Scan(&level)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return level, nil
+}
+
+// SetHintLevel records how many hints a user has seen for a problem on the given date
+func (db *DB) SetHintLevel(userID int64, problemID int, date string, level int) error {
+	query := `INSERT OR REPLACE INTO hint_usage (user_id, problem_id, date, level) VALUES (?, ?, ?, ?)`
+	_, err := db.conn.Exec(query, userID, problemID, date, level)
+	return err
+}
+
+// GetUserStats gets a user's daily challenge statistics. A challenge of today that
+// hasn't been solved yet doesn't break the current streak.
+func (db *DB) GetUserStats(userID int64, today string) (*models.UserStats, error) {
+	user, err := db.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT dc.date, dc.posted_at, p.category, s.submitted_at, s.solve_seconds, s.verified
+			  FROM daily_challenges dc
+			  JOIN problems p ON p.id = dc.problem_id
+			  LEFT JOIN submissions s ON s.date = dc.date AND s.problem_id = dc.problem_id AND s.user_id = ?
+			  ORDER BY dc.date`
+
+	rows, err := db.conn.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := &models.UserStats{
+		User:       *user,
+		ByCategory: make(map[string]int),
+	}
+	joined := user.CreatedAt.Format("2006-01-02")
+
+	var solvedDays []bool
+	var totalSolveTime time.Duration
+	for rows.Next() {
+		var date, category string
+		var postedAt time.Time
+		var submittedAt sql.NullTime
+		var solveSeconds sql.NullInt64
+		var verified sql.NullBool
+		if err := rows.Scan(&date, &postedAt, &category, &submittedAt, &solveSeconds, &verified); err != nil {
+			return nil, err
+		}
+
+		solved := submittedAt.Valid
+		if date >= joined {
+			stats.ChallengesSinceJoin++
+			if solved {
+				stats.SolvedSinceJoin++
+			}
+		}
+		if date == today && !solved {
+			// Today's challenge is still open
+			continue
+		}
+		solvedDays = append(solvedDays, solved)
+		if !solved {
+			continue
+		}
+
+		stats.TotalSolved++
+		if verified.Bool {
+			stats.Verified++
+		} else {
+			stats.SelfReported++
+		}
+		stats.ByCategory[category]++
+		if solveSeconds.Valid {
+			totalSolveTime += time.Duration(solveSeconds.Int64) * time.Second
+		} else if elapsed := submittedAt.Time.Sub(postedAt); elapsed > 0 {
+			totalSolveTime += elapsed
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	streak := 0
+	for _, solved := range solvedDays {
+		if !solved {
+			streak = 0
+			continue
+		}
+		streak++
+		if streak > stats.LongestStreak {
+			stats.LongestStreak = streak
+		}
+	}
+	stats.CurrentStreak = streak
+	stats.CategoriesSolved = len(stats.ByCategory)
+
+	if stats.TotalSolved > 0 {
+		stats.AverageSolveSeconds = int64(totalSolveTime.Seconds()) / int64(stats.TotalSolved)
+	}
+	if stats.ChallengesSinceJoin > 0 {
+		stats.ParticipationRate = float64(stats.SolvedSinceJoin) / float64(stats.ChallengesSinceJoin)
+	}
+
+	stats.Achievements, err = db.GetUserAchievements(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// CountFirstSolves counts the days on which a user was the first verified solver
+func (db *DB) CountFirstSolves(userID int64) (int, error) {
+	query := `SELECT COUNT(*) FROM submissions s
+			  WHERE s.user_id = ? AND s.verified = TRUE
+			  AND NOT EXISTS (
+				  SELECT 1 FROM submissions o
+				  WHERE o.date = s.date AND o.verified = TRUE AND o.id != s.id
+				  AND (COALESCE(o.solved_at, o.submitted_at) < COALESCE(s.solved_at, s.submitted_at)
+					   OR (COALESCE(o.solved_at, o.submitted_at) = COALESCE(s.solved_at, s.submitted_at) AND o.id < s.id))
+			  )`
+
+	var count int
+	err := db.conn.QueryRow(query, userID).Scan(&count)
+	return count, err
+}
+
+// CountFastSolves counts a user's verified solves of the given difficulty within maxSeconds of the post
+func (db *DB) CountFastSolves(userID int64, difficulty string, maxSeconds int) (int, error) {
+	query := `SELECT COUNT(*) FROM submissions s
+			  JOIN problems p ON p.id = s.problem_id
+			  WHERE s.user_id = ? AND s.verified = TRUE AND p.difficulty = ? AND s.solve_seconds <= ?`
+
+	var count int
+	err := db.conn.QueryRow(query, userID, difficulty, maxSeconds).Scan(&count)
+	return count, err
+}
+
+// CountCategories counts the distinct categories of the problems posted as daily challenges
+func (db *DB) CountCategories() (int, error) {
+	var count int
+	query := `SELECT COUNT(DISTINCT p.category) FROM daily_challenges dc JOIN problems p ON p.id = dc.problem_id`
+	err := db.conn.QueryRow(query).Scan(&count)
+	return count, err
+}
+
+// UnlockAchievement stores an achievement for a user. It reports whether it was newly unlocked.
+func (db *DB) UnlockAchievement(userID int64, achievementID string) (bool, error) {
+	query := `INSERT OR IGNORE INTO user_achievements (user_id, achievement_id) VALUES (?, ?)`
+	result, err := db.conn.Exec(query, userID, achievementID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// GetUserAchievements gets the IDs of the achievements a user has unlocked, oldest first
+func (db *DB) GetUserAchievements(userID int64) ([]string, error) {
+	query := `SELECT achievement_id FROM user_achievements WHERE user_id = ? ORDER BY unlocked_at, achievement_id`
+	rows, err := db.conn.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// AddProfileSnapshot stores a user's LeetCode stats as of now
+func (db *DB) AddProfileSnapshot(snapshot *models.ProfileSnapshot) error {
+	badges, err := json.Marshal(snapshot.Badges)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO leetcode_profile_snapshots (user_id, leetcode_username, easy_solved, medium_solved, hard_solved,
+			  total_solved, ranking, contest_rating, contests_attended, badges) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err = db.conn.Exec(query, snapshot.UserID, snapshot.LeetCodeUsername, snapshot.EasySolved, snapshot.MediumSolved,
+		snapshot.HardSolved, snapshot.TotalSolved, snapshot.Ranking, snapshot.ContestRating, snapshot.ContestsAttended, string(badges))
+	return err
+}
+
+// GetProfileHistory gets a user's LeetCode stats snapshots taken since the given time, oldest first
+func (db *DB) GetProfileHistory(userID int64, since time.Time) ([]models.ProfileSnapshot, error) {
+	query := `SELECT id, user_id, leetcode_username, easy_solved, medium_solved, hard_solved, total_solved,
+			  ranking, contest_rating, contests_attended, badges, created_at
+			  FROM leetcode_profile_snapshots
+			  WHERE user_id = ? AND created_at >= ?
+			  ORDER BY created_at, id`
+
+	rows, err := db.conn.Query(query, userID, since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []models.ProfileSnapshot
+	for rows.Next() {
+		var snapshot models.ProfileSnapshot
+		var badges string
+		err := rows.Scan(&snapshot.ID, &snapshot.UserID, &snapshot.LeetCodeUsername, &snapshot.EasySolved, &snapshot.MediumSolved,
+			&snapshot.HardSolved, &snapshot.TotalSolved, &snapshot.Ranking, &snapshot.ContestRating, &snapshot.ContestsAttended,
+			&badges, &snapshot.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(badges), &snapshot.Badges); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
+
+// GetProfileImprovements ranks users by how many problems they solved on LeetCode
+// since the given time, comparing their latest snapshot with the first one since then
+func (db *DB) GetProfileImprovements(since time.Time, limit int) ([]models.ProfileImprovement, error) {
+	query := `SELECT u.id, u.first_name, latest.leetcode_username,
+			  latest.total_solved - first.total_solved as solved_gain,
+			  latest.contest_rating - first.contest_rating as rating_gain
+			  FROM users u
+			  JOIN leetcode_profile_snapshots latest ON latest.id = (
+				  SELECT id FROM leetcode_profile_snapshots WHERE user_id = u.id ORDER BY created_at DESC, id DESC LIMIT 1
+			  )
+			  JOIN leetcode_profile_snapshots first ON first.id = (
+				  SELECT id FROM leetcode_profile_snapshots WHERE user_id = u.id AND created_at >= ? ORDER BY created_at, id LIMIT 1
+			  )
+			  WHERE latest.id != first.id
+			  ORDER BY solved_gain DESC, rating_gain DESC, u.first_name ASC
+			  LIMIT ?`
+
+	rows, err := db.conn.Query(query, since.UTC().Format("2006-01-02 15:04:05"), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var improvements []models.ProfileImprovement
+	for rows.Next() {
+		var improvement models.ProfileImprovement
+		err := rows.Scan(&improvement.UserID, &improvement.FirstName, &improvement.LeetCodeUsername,
+			&improvement.SolvedGain, &improvement.RatingGain)
+		if err != nil {
+			return nil, err
+		}
+		improvements = append(improvements, improvement)
+	}
+
+	return improvements, nil
+}
+
+// AddOutboundWebhook registers a URL for the given events and returns its ID
+func (db *DB) AddOutboundWebhook(webhook *models.OutboundWebhook) (int, error) {
+	query := `INSERT INTO outbound_webhooks (url, secret, events) VALUES (?, ?, ?)`
+	result, err := db.conn.Exec(query, webhook.URL, webhook.Secret, strings.Join(webhook.Events, ","))
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	return int(id), err
+}
+
+// GetOutboundWebhooks gets all registered webhooks
+func (db *DB) GetOutboundWebhooks() ([]models.OutboundWebhook, error) {
+	query := `SELECT id, url, secret, events, created_at FROM outbound_webhooks ORDER BY id`
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []models.OutboundWebhook
+	for rows.Next() {
+		var webhook models.OutboundWebhook
+		var events string
+		if err := rows.Scan(&webhook.ID, &webhook.URL, &webhook.Secret, &events, &webhook.CreatedAt); err != nil {
+			return nil, err
+		}
+		webhook.Events = strings.Split(events, ",")
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// RemoveOutboundWebhook removes a webhook and its undelivered events, reporting whether it existed
+func (db *DB) RemoveOutboundWebhook(id int) (bool, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM webhook_deliveries WHERE webhook_id = ?`, id); err != nil {
+		return false, err
+	}
+	result, err := tx.Exec(`DELETE FROM outbound_webhooks WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, tx.Commit()
+}
+
+// HasWebhooksFor reports whether any webhook is subscribed to an event
+func (db *DB) HasWebhooksFor(event string) (bool, error) {
+	query := `SELECT COUNT(*) FROM outbound_webhooks WHERE ',' || events || ',' LIKE '%,' || ? || ',%' OR events = '*'`
+	var count int
+	if err := db.conn.QueryRow(query, event).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// EnqueueWebhookDeliveries queues an event for every webhook subscribed to it and returns
+// how many deliveries were queued
+func (db *DB) EnqueueWebhookDeliveries(event, payload string) (int, error) {
+	query := `INSERT INTO webhook_deliveries (webhook_id, event, payload)
+			  SELECT id, ?, ? FROM outbound_webhooks
+			  WHERE ',' || events || ',' LIKE '%,' || ? || ',%' OR events = '*'`
+	result, err := db.conn.Exec(query, event, payload, event)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+// GetDueWebhookDeliveries gets pending deliveries whose next attempt is due, oldest first
+func (db *DB) GetDueWebhookDeliveries(limit int) ([]models.WebhookDelivery, error) {
+	query := `SELECT d.id, d.webhook_id, w.url, w.secret, d.event, d.payload, d.attempts
+			  FROM webhook_deliveries d
+			  JOIN outbound_webhooks w ON w.id = d.webhook_id
+			  WHERE d.status = 'pending' AND d.next_attempt_at <= CURRENT_TIMESTAMP
+			  ORDER BY d.id
+			  LIMIT ?`
+
+	rows, err := db.conn.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		err := rows.Scan(&delivery.ID, &delivery.WebhookID, &delivery.URL, &delivery.Secret,
+			&delivery.Event, &delivery.Payload, &delivery.Attempts)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// MarkWebhookDelivered marks a delivery as delivered
+func (db *DB) MarkWebhookDelivered(id int) error {
+	query := `UPDATE webhook_deliveries SET status = 'delivered', attempts = attempts + 1, last_error = '' WHERE id = ?`
+	_, err := db.conn.Exec(query, id)
+	return err
+}
+
+// RetryWebhookDelivery records a failed attempt and schedules the next one after delay
+func (db *DB) RetryWebhookDelivery(id int, delay time.Duration, lastError string) error {
+	query := `UPDATE webhook_deliveries SET attempts = attempts + 1, last_error = ?,
+			  next_attempt_at = datetime('now', ?) WHERE id = ?`
+	_, err := db.conn.Exec(query, lastError, fmt.Sprintf("+%d seconds", int(delay.Seconds())), id)
+	return err
+}
+
+// FailWebhookDelivery records a failed attempt and gives up on the delivery
+func (db *DB) FailWebhookDelivery(id int, lastError string) error {
+	query := `UPDATE webhook_deliveries SET status = 'failed', attempts = attempts + 1, last_error = ? WHERE id = ?`
+	_, err := db.conn.Exec(query, lastError, id)
+	return err
+}
+
+// CountWebhookDeliveries counts the deliveries of a webhook by status
+func (db *DB) CountWebhookDeliveries(webhookID int) (map[string]int, error) {
+	query := `SELECT status, COUNT(*) FROM webhook_deliveries WHERE webhook_id = ? GROUP BY status`
+
+	rows, err := db.conn.Query(query, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// GetGroupMessageID gets the ID of a message of the given kind (e.g. "announcement") posted
+// to a chat on another platform for a date
+func (db *DB) GetGroupMessageID(platform, chatID, date, kind string) (string, error) {
+	query := `SELECT message_id FROM group_messages WHERE platform = ? AND chat_id = ? AND date = ? AND kind = ?`
+	var messageID string
+	err := db.conn.QueryRow(query, platform, chatID, date, kind).Scan(&messageID)
+	return messageID, err
 }
 
-// RegisterLeetcodeProfile registers a leetcode profile for a user
-func (db *DB) RegisterLeetcodeProfile(userID int64, leetcodeUsername string) error {
-	query := `INSERT OR REPLACE INTO user_leetcode_profiles (user_id, leetcode_username, created_at) 
-			  VALUES (?, ?, COALESCE((SELECT created_at FROM user_leetcode_profiles WHERE user_id = ?), CURRENT_TIMESTAMP))`
-	_, err := db.conn.Exec(query, userID, leetcodeUsername, userID)
+// SetGroupMessageID stores the ID of a message posted to a chat on another platform
+func (db *DB) SetGroupMessageID(platform, chatID, date, kind, messageID string) error {
+	query := `INSERT OR REPLACE INTO group_messages (platform, chat_id, date, kind, message_id) VALUES (?, ?, ?, ?, ?)`
+	_, err := db.conn.Exec(query, platform, chatID, date, kind, messageID)
 	return err
 }
@@ -0,0 +1,467 @@
+package database
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"leetcode-telegram-bot/internal/models"
+)
+
+// newTestDB opens a fresh database in a temporary directory
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// addProblem adds a problem with the given URL and returns it as stored
+func addProblem(t *testing.T, db *DB, url string) *models.Problem {
+	t.Helper()
+	if err := db.AddProblem(&models.Problem{Title: url, URL: url, Category: "Array"}); err != nil {
+		t.Fatalf("AddProblem(%q): %v", url, err)
+	}
+	problem, err := db.GetProblemByTitle(url)
+	if err != nil {
+		t.Fatalf("GetProblemByTitle(%q): %v", url, err)
+	}
+	return problem
+}
+
+// postChallenge records a problem as the challenge of a date, the way the bot posts one
+func postChallenge(t *testing.T, db *DB, problem *models.Problem, date string) int {
+	t.Helper()
+	day, err := db.IncrementDayNumber()
+	if err != nil {
+		t.Fatalf("IncrementDayNumber: %v", err)
+	}
+	if err := db.AddDailyChallenge(&models.DailyChallenge{ProblemID: problem.ID, Date: date, DayNumber: day}); err != nil {
+		t.Fatalf("AddDailyChallenge(%s): %v", date, err)
+	}
+	if err := db.MarkProblemAsUsed(problem.ID); err != nil {
+		t.Fatalf("MarkProblemAsUsed: %v", err)
+	}
+	return day
+}
+
+// solve records a verified submission, a saved solution and a hint for a user on a date
+func solve(t *testing.T, db *DB, userID int64, problem *models.Problem, date string) {
+	t.Helper()
+	if err := db.AddSubmission(&models.Submission{UserID: userID, ProblemID: problem.ID, Date: date, Verified: true}); err != nil {
+		t.Fatalf("AddSubmission: %v", err)
+	}
+	if err := db.SaveSolution(&models.Solution{UserID: userID, ProblemID: problem.ID, Date: date, Language: "go", Code: "return"}); err != nil {
+		t.Fatalf("SaveSolution: %v", err)
+	}
+	if err := db.SetHintLevel(userID, problem.ID, date, 1); err != nil {
+		t.Fatalf("SetHintLevel: %v", err)
+	}
+}
+
+// countRows counts the rows of a table on a date
+func countRows(t *testing.T, db *DB, table, date string) int {
+	t.Helper()
+	var count int
+	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE date = ?`, date).Scan(&count); err != nil {
+		t.Fatalf("counting %s: %v", table, err)
+	}
+	return count
+}
+
+// isUsed reports whether a problem has been marked as used
+func isUsed(t *testing.T, db *DB, problem *models.Problem) bool {
+	t.Helper()
+	stored, err := db.GetProblemByTitle(problem.Title)
+	if err != nil {
+		t.Fatalf("GetProblemByTitle(%q): %v", problem.Title, err)
+	}
+	return stored.Used
+}
+
+func TestGetProblemBySlug(t *testing.T) {
+	db := newTestDB(t)
+	twoSum := addProblem(t, db, "https://leetcode.com/problems/two-sum/")
+	codeforces := addProblem(t, db, "https://codeforces.com/problemset/problem/1850/a")
+	addProblem(t, db, "https://leetcode.com/problems/two_sum_ii/")
+
+	tests := []struct {
+		slug string
+		want int // 0 when no problem should be found
+	}{
+		{"two-sum", twoSum.ID},
+		{"TWO-SUM", twoSum.ID},
+		{"1850A", codeforces.ID},
+		{"1850a", codeforces.ID},
+		{"two", 0},
+		{"two%", 0},
+		{"two_sum", 0},
+		{"_", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		problem, err := db.GetProblemBySlug(tt.slug)
+		switch {
+		case tt.want == 0 && err == nil:
+			t.Errorf("GetProblemBySlug(%q) = %q, want no problem", tt.slug, problem.URL)
+		case tt.want != 0 && err != nil:
+			t.Errorf("GetProblemBySlug(%q): %v", tt.slug, err)
+		case tt.want != 0 && problem.ID != tt.want:
+			t.Errorf("GetProblemBySlug(%q) = problem %d, want %d", tt.slug, problem.ID, tt.want)
+		}
+	}
+}
+
+func TestReplaceDailyChallengeProblem(t *testing.T) {
+	db := newTestDB(t)
+	const date = "2026-01-05"
+	old := addProblem(t, db, "https://leetcode.com/problems/two-sum/")
+	replacement := addProblem(t, db, "https://leetcode.com/problems/3sum/")
+	day := postChallenge(t, db, old, date)
+	if err := db.EnqueueProblem(replacement.ID, ""); err != nil {
+		t.Fatalf("EnqueueProblem: %v", err)
+	}
+	solve(t, db, 1, old, date)
+
+	if err := db.ReplaceDailyChallengeProblem(date, replacement.ID); err != nil {
+		t.Fatalf("ReplaceDailyChallengeProblem: %v", err)
+	}
+
+	challenge, err := db.GetDailyChallenge(date)
+	if err != nil {
+		t.Fatalf("GetDailyChallenge: %v", err)
+	}
+	if challenge.ProblemID != replacement.ID || challenge.DayNumber != day {
+		t.Errorf("challenge = problem %d on day %d, want problem %d on day %d", challenge.ProblemID, challenge.DayNumber, replacement.ID, day)
+	}
+	if isUsed(t, db, old) {
+		t.Error("replaced problem is still marked as used")
+	}
+	if !isUsed(t, db, replacement) {
+		t.Error("new problem is not marked as used")
+	}
+	if entries, err := db.GetQueue(); err != nil || len(entries) != 0 {
+		t.Errorf("queue = %v (%v), want the new problem removed from it", entries, err)
+	}
+	for _, table := range []string{"submissions", "solutions", "hint_usage"} {
+		if n := countRows(t, db, table, date); n != 0 {
+			t.Errorf("%d rows left in %s, want those of the replaced problem dropped", n, table)
+		}
+	}
+}
+
+func TestSkipDay(t *testing.T) {
+	tests := []struct {
+		name   string
+		posted bool
+	}{
+		{"before posting", false},
+		{"after posting", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			const date = "2026-01-05"
+			problem := addProblem(t, db, "https://leetcode.com/problems/two-sum/")
+			before, err := db.GetCurrentDayNumber()
+			if err != nil {
+				t.Fatalf("GetCurrentDayNumber: %v", err)
+			}
+			if tt.posted {
+				postChallenge(t, db, problem, date)
+				solve(t, db, 1, problem, date)
+			}
+
+			challenge, err := db.SkipDay(date)
+			if err != nil {
+				t.Fatalf("SkipDay: %v", err)
+			}
+			if (challenge != nil) != tt.posted {
+				t.Errorf("SkipDay returned challenge %v, want one only if it was posted", challenge)
+			}
+
+			if skipped, err := db.IsDaySkipped(date); err != nil || !skipped {
+				t.Errorf("IsDaySkipped = %v (%v), want true", skipped, err)
+			}
+			if _, err := db.GetDailyChallenge(date); err == nil {
+				t.Error("the skipped challenge is still stored")
+			}
+			if isUsed(t, db, problem) {
+				t.Error("the skipped problem is still marked as used")
+			}
+			if after, err := db.GetCurrentDayNumber(); err != nil || after != before {
+				t.Errorf("day counter = %d (%v), want it back at %d", after, err, before)
+			}
+			for _, table := range []string{"submissions", "solutions", "hint_usage"} {
+				if n := countRows(t, db, table, date); n != 0 {
+					t.Errorf("%d rows left in %s, want those of the skipped day dropped", n, table)
+				}
+			}
+		})
+	}
+}
+
+func TestGetNextQueuedProblem(t *testing.T) {
+	db := newTestDB(t)
+	undated := addProblem(t, db, "https://leetcode.com/problems/two-sum/")
+	later := addProblem(t, db, "https://leetcode.com/problems/3sum/")
+	earlier := addProblem(t, db, "https://leetcode.com/problems/4sum/")
+	posted := addProblem(t, db, "https://leetcode.com/problems/valid-anagram/")
+	queue := []struct {
+		problem *models.Problem
+		date    string
+	}{
+		{undated, ""},
+		{later, "2026-01-10"},
+		{earlier, "2026-01-07"},
+		{posted, "2026-01-06"},
+	}
+	for _, entry := range queue {
+		if err := db.EnqueueProblem(entry.problem.ID, entry.date); err != nil {
+			t.Fatalf("EnqueueProblem: %v", err)
+		}
+	}
+	if err := db.MarkProblemAsUsed(posted.ID); err != nil {
+		t.Fatalf("MarkProblemAsUsed: %v", err)
+	}
+
+	tests := []struct {
+		date string
+		want *models.Problem
+	}{
+		{"2026-01-06", undated},
+		{"2026-01-07", earlier},
+		{"2026-01-09", earlier},
+		{"2026-01-10", earlier},
+	}
+	for _, tt := range tests {
+		problem, err := db.GetNextQueuedProblem(tt.date)
+		if err != nil {
+			t.Errorf("GetNextQueuedProblem(%s): %v", tt.date, err)
+		} else if problem.ID != tt.want.ID {
+			t.Errorf("GetNextQueuedProblem(%s) = %q, want %q", tt.date, problem.URL, tt.want.URL)
+		}
+	}
+
+	if queued, err := db.IsProblemQueued(posted.ID); err != nil || queued {
+		t.Errorf("IsProblemQueued(posted) = %v (%v), want the entry dropped", queued, err)
+	}
+}
+
+// loadTrack loads a track of LeetCode problems with the given slugs, running over the given dates
+func loadTrack(t *testing.T, db *DB, name, start, end string, slugs ...string) *models.Track {
+	t.Helper()
+	data := "- name: " + name + "\n  start: " + start + "\n  end: " + end + "\n  problems:\n"
+	for _, slug := range slugs {
+		data += "    - title: " + slug + "\n      url: https://leetcode.com/problems/" + slug + "/\n"
+	}
+	var tracks models.TracksData
+	if err := yaml.Unmarshal([]byte(data), &tracks); err != nil {
+		t.Fatalf("parsing track: %v", err)
+	}
+	if err := db.LoadTracksFromYAML(tracks); err != nil {
+		t.Fatalf("LoadTracksFromYAML: %v", err)
+	}
+	track, err := db.GetActiveTrack(start)
+	if err != nil {
+		t.Fatalf("GetActiveTrack(%s): %v", start, err)
+	}
+	return track
+}
+
+func TestGetNextTrackProblem(t *testing.T) {
+	db := newTestDB(t)
+	track := loadTrack(t, db, "Graphs", "2026-01-05", "2026-01-09", "number-of-islands", "clone-graph", "course-schedule")
+
+	tests := []struct {
+		posted string // Posted as the track's challenge before picking, if any
+		used   string // Posted some other way before picking, if any
+		want   string
+	}{
+		{"", "", "number-of-islands"},
+		{"number-of-islands", "", "clone-graph"},
+		{"", "clone-graph", "course-schedule"},
+		{"course-schedule", "", ""},
+	}
+	for i, tt := range tests {
+		date := fmt.Sprintf("2026-01-%02d", 5+i)
+		for _, slug := range []string{tt.posted, tt.used} {
+			if slug == "" {
+				continue
+			}
+			problem, err := db.GetProblemBySlug(slug)
+			if err != nil {
+				t.Fatalf("GetProblemBySlug(%q): %v", slug, err)
+			}
+			if err := db.MarkProblemAsUsed(problem.ID); err != nil {
+				t.Fatalf("MarkProblemAsUsed: %v", err)
+			}
+			if slug == tt.posted {
+				if err := db.MarkTrackProblemPosted(problem.ID, date); err != nil {
+					t.Fatalf("MarkTrackProblemPosted: %v", err)
+				}
+			}
+		}
+
+		problem, err := db.GetNextTrackProblem(track.ID)
+		switch {
+		case tt.want == "" && err == nil:
+			t.Errorf("step %d: GetNextTrackProblem = %q, want the track finished", i, problem.Title)
+		case tt.want != "" && err != nil:
+			t.Errorf("step %d: GetNextTrackProblem: %v", i, err)
+		case tt.want != "" && problem.Title != tt.want:
+			t.Errorf("step %d: GetNextTrackProblem = %q, want %q", i, problem.Title, tt.want)
+		}
+	}
+}
+
+func TestGetRandomUnusedProblem(t *testing.T) {
+	db := newTestDB(t)
+	free := addProblem(t, db, "https://leetcode.com/problems/two-sum/")
+	used := addProblem(t, db, "https://leetcode.com/problems/3sum/")
+	queued := addProblem(t, db, "https://leetcode.com/problems/4sum/")
+	if err := db.MarkProblemAsUsed(used.ID); err != nil {
+		t.Fatalf("MarkProblemAsUsed: %v", err)
+	}
+	if err := db.EnqueueProblem(queued.ID, ""); err != nil {
+		t.Fatalf("EnqueueProblem: %v", err)
+	}
+	loadTrack(t, db, "Graphs", "2026-01-05", "2026-01-09", "number-of-islands")
+
+	tests := []struct {
+		date    string
+		allowed []string
+	}{
+		{"2026-01-01", []string{free.Title}},
+		{"2026-01-09", []string{free.Title}},
+		{"2026-01-10", []string{free.Title, "number-of-islands"}},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			problem, err := db.GetRandomUnusedProblem(tt.date)
+			if err != nil {
+				t.Fatalf("GetRandomUnusedProblem(%s): %v", tt.date, err)
+			}
+			if !slices.Contains(tt.allowed, problem.Title) {
+				t.Errorf("GetRandomUnusedProblem(%s) = %q, want one of %q", tt.date, problem.Title, tt.allowed)
+				break
+			}
+		}
+	}
+}
+
+func TestLeaderboardHintPenalty(t *testing.T) {
+	db := newTestDB(t)
+	problem := addProblem(t, db, "https://leetcode.com/problems/two-sum/")
+	for _, user := range []models.User{{ID: 1, FirstName: "Ann"}, {ID: 2, FirstName: "Bob"}, {ID: 3, FirstName: "Cat"}} {
+		if err := db.AddUser(&user); err != nil {
+			t.Fatalf("AddUser: %v", err)
+		}
+	}
+	solves := []struct {
+		userID int64
+		date   string
+		hints  int
+	}{
+		{1, "2026-01-05", 0},
+		{1, "2026-01-06", 1},
+		{1, "2026-01-07", 3},
+		{2, "2026-01-05", 0},
+		{2, "2026-01-06", 0},
+	}
+	for _, s := range solves {
+		if err := db.AddSubmission(&models.Submission{UserID: s.userID, ProblemID: problem.ID, Date: s.date}); err != nil {
+			t.Fatalf("AddSubmission: %v", err)
+		}
+		if s.hints > 0 {
+			if err := db.SetHintLevel(s.userID, problem.ID, s.date, s.hints); err != nil {
+				t.Fatalf("SetHintLevel: %v", err)
+			}
+		}
+	}
+
+	tests := []struct {
+		from, to string // Whole history when empty
+		penalty  float64
+		want     string
+	}{
+		{"", "", 0, "Ann 3/3, Bob 2/2, Cat 0/0"},
+		{"", "", 0.25, "Ann 2/3, Bob 2/2, Cat 0/0"},
+		{"", "", 0.5, "Bob 2/2, Ann 1.5/3, Cat 0/0"},
+		{"", "", 1, "Bob 2/2, Ann 1/3, Cat 0/0"},
+		{"2026-01-06", "2026-01-07", 0, "Ann 2/2, Bob 1/1"},
+		{"2026-01-06", "2026-01-07", 0.5, "Bob 1/1, Ann 0.5/2"},
+	}
+	for _, tt := range tests {
+		var leaderboard []models.LeaderboardEntry
+		var err error
+		if tt.from == "" {
+			leaderboard, err = db.GetLeaderboard(10, tt.penalty)
+		} else {
+			leaderboard, err = db.GetLeaderboardBetween(tt.from, tt.to, 10, tt.penalty)
+		}
+		if err != nil {
+			t.Fatalf("leaderboard %s..%s: %v", tt.from, tt.to, err)
+		}
+		var got []string
+		for _, entry := range leaderboard {
+			got = append(got, fmt.Sprintf("%s %g/%d", entry.FirstName, entry.Points, entry.TotalSolved))
+		}
+		if strings.Join(got, ", ") != tt.want {
+			t.Errorf("leaderboard %s..%s with penalty %g = %q, want %q", tt.from, tt.to, tt.penalty, strings.Join(got, ", "), tt.want)
+		}
+	}
+}
+
+func TestGetUserStatsStreaks(t *testing.T) {
+	tests := []struct {
+		days    string // One character per daily challenge up to today: x solved, . missed
+		current int
+		longest int
+	}{
+		{"", 0, 0},
+		{"...", 0, 0},
+		{"xxx.xx", 2, 3},
+		{"xxx.x.", 1, 3},
+		{"xx.xxx.", 3, 3},
+		{"xxxx..", 0, 4},
+		{"x.x.x", 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.days, func(t *testing.T) {
+			db := newTestDB(t)
+			problem := addProblem(t, db, "https://leetcode.com/problems/two-sum/")
+			if err := db.AddUser(&models.User{ID: 1, FirstName: "Ann"}); err != nil {
+				t.Fatalf("AddUser: %v", err)
+			}
+			today := "2026-01-01"
+			for i, day := range tt.days {
+				today = fmt.Sprintf("2026-01-%02d", i+1)
+				postChallenge(t, db, problem, today)
+				if day == 'x' {
+					if err := db.AddSubmission(&models.Submission{UserID: 1, ProblemID: problem.ID, Date: today}); err != nil {
+						t.Fatalf("AddSubmission: %v", err)
+					}
+				}
+			}
+
+			stats, err := db.GetUserStats(1, today)
+			if err != nil {
+				t.Fatalf("GetUserStats: %v", err)
+			}
+			if stats.CurrentStreak != tt.current || stats.LongestStreak != tt.longest {
+				t.Errorf("streaks = %d current, %d longest, want %d, %d", stats.CurrentStreak, stats.LongestStreak, tt.current, tt.longest)
+			}
+			if want := strings.Count(tt.days, "x"); stats.TotalSolved != want {
+				t.Errorf("TotalSolved = %d, want %d", stats.TotalSolved, want)
+			}
+		})
+	}
+}
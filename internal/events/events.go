@@ -0,0 +1,267 @@
+package events
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"leetcode-telegram-bot/internal/database"
+	"leetcode-telegram-bot/internal/metrics"
+	"leetcode-telegram-bot/internal/models"
+)
+
+// Events sent to outbound webhooks
+const (
+	ChallengePosted    = "challenge.posted"
+	SubmissionRecorded = "submission.recorded"
+	ReminderSent       = "reminder.sent"
+	StreakBroken       = "streak.broken"
+)
+
+// All lists every event a webhook can subscribe to
+var All = []string{ChallengePosted, SubmissionRecorded, ReminderSent, StreakBroken}
+
+// Wildcard subscribes a webhook to every event, including ones added later
+const Wildcard = "*"
+
+// Headers sent with every delivery. The signature is "sha256=" followed by the hex HMAC-SHA256
+// of the request body, keyed with the webhook's secret.
+const (
+	EventHeader     = "X-LeetCode-Bot-Event"
+	DeliveryHeader  = "X-LeetCode-Bot-Delivery"
+	SignatureHeader = "X-LeetCode-Bot-Signature"
+)
+
+const (
+	// maxAttempts is how many times a delivery is tried before giving up on it
+	maxAttempts = 8
+	// batchSize is how many due deliveries are sent per run
+	batchSize = 50
+	// The delay before a retry doubles with every failed attempt, up to maxRetryDelay
+	firstRetryDelay = time.Minute
+	maxRetryDelay   = 6 * time.Hour
+)
+
+// Valid reports whether an event can be subscribed to
+func Valid(event string) bool {
+	if event == Wildcard {
+		return true
+	}
+	for _, known := range All {
+		if event == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Envelope is the JSON body of a delivery. Text is a one-line summary for chat services.
+type Envelope struct {
+	Event     string      `json:"event"`
+	Text      string      `json:"text"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// ChallengePostedData is the data of a challenge.posted event
+type ChallengePostedData struct {
+	DayNumber int             `json:"day_number"`
+	Date      string          `json:"date"`
+	Problem   *models.Problem `json:"problem"`
+}
+
+// SubmissionRecordedData is the data of a submission.recorded event
+type SubmissionRecordedData struct {
+	DayNumber  int                `json:"day_number"`
+	User       *models.User       `json:"user"`
+	Problem    *models.Problem    `json:"problem"`
+	Submission *models.Submission `json:"submission"`
+}
+
+// ReminderSentData is the data of a reminder.sent event
+type ReminderSentData struct {
+	DayNumber int             `json:"day_number"`
+	Date      string          `json:"date"`
+	Problem   *models.Problem `json:"problem"`
+	Users     []models.User   `json:"users"` // Members who were reminded
+}
+
+// StreakBrokenData is the data of a streak.broken event
+type StreakBrokenData struct {
+	Date   string       `json:"date"`
+	User   *models.User `json:"user"`
+	Streak int          `json:"streak"` // Length of the streak that ended
+}
+
+// Dispatcher queues events for the webhooks subscribed to them and delivers them with retries.
+// The queue is a database table, so deliveries survive restarts.
+type Dispatcher struct {
+	db     *database.DB
+	client *http.Client
+
+	// Held while delivering, so a slow run is never overlapped by the next one sending the same rows
+	delivering sync.Mutex
+}
+
+// New creates a dispatcher
+func New(db *database.DB) *Dispatcher {
+	return &Dispatcher{
+		db:     db,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Publish queues an event for every webhook subscribed to it. Failures are only logged so
+// webhooks never get in the way of the bot.
+func (d *Dispatcher) Publish(event, text string, data interface{}) {
+	payload, err := json.Marshal(Envelope{Event: event, Text: text, CreatedAt: time.Now(), Data: data})
+	if err != nil {
+		log.Printf("Error encoding %s event: %v", event, err)
+		return
+	}
+
+	queued, err := d.db.EnqueueWebhookDeliveries(event, string(payload))
+	if err != nil {
+		log.Printf("Error queueing %s event: %v", event, err)
+		return
+	}
+	if queued > 0 {
+		log.Printf("Queued %s event for %d webhooks", event, queued)
+	}
+}
+
+// Subscribed reports whether any webhook wants an event, to skip building events nobody receives
+func (d *Dispatcher) Subscribed(event string) bool {
+	subscribed, err := d.db.HasWebhooksFor(event)
+	if err != nil {
+		log.Printf("Error checking webhooks for %s: %v", event, err)
+		return false
+	}
+	return subscribed
+}
+
+// Deliver sends the deliveries that are due. Failed ones are retried later with a growing
+// delay and dropped after maxAttempts. It does nothing while a previous run is still going.
+func (d *Dispatcher) Deliver() error {
+	if !d.delivering.TryLock() {
+		return nil
+	}
+	defer d.delivering.Unlock()
+
+	deliveries, err := d.db.GetDueWebhookDeliveries(batchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get due webhook deliveries: %w", err)
+	}
+
+	for _, delivery := range deliveries {
+		sendErr := d.send(delivery)
+		if sendErr == nil {
+			metrics.WebhookDeliveries.Inc("ok")
+			if err := d.db.MarkWebhookDelivered(delivery.ID); err != nil {
+				log.Printf("Error marking webhook delivery %d as delivered: %v", delivery.ID, err)
+			}
+			continue
+		}
+
+		attempts := delivery.Attempts + 1
+		if attempts >= maxAttempts {
+			metrics.WebhookDeliveries.Inc("failed")
+			log.Printf("Giving up on webhook delivery %d to %s after %d attempts: %v", delivery.ID, delivery.URL, attempts, sendErr)
+			err = d.db.FailWebhookDelivery(delivery.ID, sendErr.Error())
+		} else {
+			metrics.WebhookDeliveries.Inc("error")
+			log.Printf("Error delivering %s to %s, retrying: %v", delivery.Event, delivery.URL, sendErr)
+			err = d.db.RetryWebhookDelivery(delivery.ID, retryDelay(attempts), sendErr.Error())
+		}
+		if err != nil {
+			log.Printf("Error updating webhook delivery %d: %v", delivery.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// send posts a delivery to its webhook. Any status other than 2xx is a failure.
+func (d *Dispatcher) send(delivery models.WebhookDelivery) error {
+	body, err := requestBody(delivery.URL, delivery.Payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "leetcode-telegram-bot")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, strconv.Itoa(delivery.ID))
+	req.Header.Set(SignatureHeader, "sha256="+Sign(delivery.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// requestBody formats a payload for the webhook URL. Slack and Discord incoming webhooks only
+// take their own message format, so they get the text summary. Other URLs get the envelope.
+func requestBody(webhookURL, payload string) ([]byte, error) {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope Envelope
+	switch {
+	case parsed.Host == "hooks.slack.com":
+		if err := json.Unmarshal([]byte(payload), &envelope); err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]string{"text": envelope.Text})
+	case (parsed.Host == "discord.com" || parsed.Host == "discordapp.com") && strings.HasPrefix(parsed.Path, "/api/webhooks/"):
+		if err := json.Unmarshal([]byte(payload), &envelope); err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]string{"content": envelope.Text})
+	default:
+		return []byte(payload), nil
+	}
+}
+
+// Sign returns the hex HMAC-SHA256 of body keyed with secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryDelay is how long to wait after the given number of failed attempts
+func retryDelay(attempts int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
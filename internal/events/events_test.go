@@ -0,0 +1,78 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		secret string
+		body   string
+		want   string
+	}{
+		// RFC 4231 test case 2
+		{"Jefe", "what do ya want for nothing?", "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"},
+		{"", "", "b613679a0814d9ec772f95d778c35fc5ff1697c493715653c6c712144292c5ad"},
+	}
+	for _, tt := range tests {
+		if got := Sign(tt.secret, []byte(tt.body)); got != tt.want {
+			t.Errorf("Sign(%q, %q) = %s, want %s", tt.secret, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{7, 64 * time.Minute},
+		{9, 256 * time.Minute},
+		{10, 6 * time.Hour},
+		{100, 6 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestRequestBody(t *testing.T) {
+	const payload = `{"event":"challenge.posted","text":"Day 10: Two Sum","created_at":"2026-01-05T00:00:00Z","data":null}`
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://hooks.slack.com/services/T/B/X", `{"text":"Day 10: Two Sum"}`},
+		{"https://discord.com/api/webhooks/1/abc", `{"content":"Day 10: Two Sum"}`},
+		{"https://discordapp.com/api/webhooks/1/abc", `{"content":"Day 10: Two Sum"}`},
+		{"https://discord.com/other", payload},
+		{"https://example.com/hook", payload},
+	}
+	for _, tt := range tests {
+		got, err := requestBody(tt.url, payload)
+		if err != nil {
+			t.Errorf("requestBody(%q): %v", tt.url, err)
+		} else if string(got) != tt.want {
+			t.Errorf("requestBody(%q) = %s, want %s", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+	for _, event := range append(All, Wildcard) {
+		if !Valid(event) {
+			t.Errorf("Valid(%q) = false, want true", event)
+		}
+	}
+	for _, event := range []string{"", "challenge", "challenge.posted.extra", "**"} {
+		if Valid(event) {
+			t.Errorf("Valid(%q) = true, want false", event)
+		}
+	}
+}
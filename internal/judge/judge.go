@@ -0,0 +1,116 @@
+package judge
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Platform is an online judge problems can be posted from
+type Platform string
+
+const (
+	LeetCode   Platform = "leetcode"
+	Codeforces Platform = "codeforces"
+	HackerRank Platform = "hackerrank"
+	NeetCode   Platform = "neetcode"
+)
+
+// Platforms lists the supported judges
+var Platforms = []Platform{LeetCode, Codeforces, HackerRank, NeetCode}
+
+// hosts maps the host of a problem URL to its judge
+var hosts = map[string]Platform{
+	"leetcode.com":   LeetCode,
+	"leetcode.cn":    LeetCode,
+	"codeforces.com": Codeforces,
+	"hackerrank.com": HackerRank,
+	"neetcode.io":    NeetCode,
+}
+
+// ParsePlatform parses a judge name such as "codeforces"
+func ParsePlatform(value string) (Platform, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, platform := range Platforms {
+		if string(platform) == value {
+			return platform, nil
+		}
+	}
+	return "", fmt.Errorf("unknown platform %q", value)
+}
+
+// PlatformFromURL detects the judge of a problem from its URL, defaulting to LeetCode
+func PlatformFromURL(problemURL string) Platform {
+	u, err := url.Parse(problemURL)
+	if err != nil {
+		return LeetCode
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if platform, ok := hosts[host]; ok {
+		return platform
+	}
+	return LeetCode
+}
+
+// ProblemSlug extracts the short name a problem is looked up by from its URL: the slug on
+// LeetCode, NeetCode and HackerRank (e.g. "two-sum") and the problem ID on Codeforces (e.g.
+// "1850A"). It returns an empty string for URLs it doesn't recognize.
+func ProblemSlug(problemURL string) string {
+	parts := strings.Split(strings.Trim(problemURL, "/"), "/")
+	codeforces := PlatformFromURL(problemURL) == Codeforces
+	for i, part := range parts {
+		switch {
+		case codeforces && part == "problem" && i+2 < len(parts):
+			// problemset/problem/<contest>/<index>
+			return parts[i+1] + strings.ToUpper(parts[i+2])
+		case codeforces && (part == "contest" || part == "gym") && i+3 < len(parts) && parts[i+2] == "problem":
+			// contest/<contest>/problem/<index>
+			return parts[i+1] + strings.ToUpper(parts[i+3])
+		case !codeforces && (part == "problems" || part == "challenges") && i+1 < len(parts):
+			// problems/<slug> on LeetCode and NeetCode, challenges/<slug> on HackerRank
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// Name returns the display name of the judge
+func (p Platform) Name() string {
+	switch p {
+	case Codeforces:
+		return "Codeforces"
+	case HackerRank:
+		return "HackerRank"
+	case NeetCode:
+		return "NeetCode"
+	default:
+		return "LeetCode"
+	}
+}
+
+// Account is a user's account on a judge
+type Account struct {
+	Handle string
+	Site   string // Only used by judges with several sites, e.g. leetcode.cn
+}
+
+// Accepted is an accepted submission on a judge
+type Accepted struct {
+	ProblemID string
+	Title     string
+	At        time.Time
+}
+
+// Checker verifies solves on a judge. Judges without a checker only get self-reported solves.
+type Checker interface {
+	// ProblemID extracts the judge's identifier of a problem from its URL, empty if unknown
+	ProblemID(problemURL string) string
+
+	// RecentAccepted lists the recent accepted submissions of an account
+	RecentAccepted(account Account) ([]Accepted, error)
+
+	// ProfileText returns the public profile fields the owner of an account can edit.
+	// Verification tokens are looked up in it.
+	ProfileText(account Account) (string, error)
+}
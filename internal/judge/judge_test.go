@@ -0,0 +1,27 @@
+package judge
+
+import "testing"
+
+func TestProblemSlug(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://leetcode.com/problems/two-sum/", "two-sum"},
+		{"https://leetcode.com/problems/two-sum/description/", "two-sum"},
+		{"https://leetcode.cn/problems/two-sum/", "two-sum"},
+		{"https://neetcode.io/problems/duplicate-integer", "duplicate-integer"},
+		{"https://www.hackerrank.com/challenges/solve-me-first/problem", "solve-me-first"},
+		{"https://codeforces.com/problemset/problem/1850/a", "1850A"},
+		{"https://codeforces.com/contest/1850/problem/B2", "1850B2"},
+		{"https://codeforces.com/gym/104114/problem/c", "104114C"},
+		{"https://leetcode.com/problemset/", ""},
+		{"https://codeforces.com/contest/1850", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ProblemSlug(tt.url); got != tt.want {
+			t.Errorf("ProblemSlug(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
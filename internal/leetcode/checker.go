@@ -0,0 +1,37 @@
+package leetcode
+
+import (
+	"leetcode-telegram-bot/internal/judge"
+)
+
+// Checker verifies solves on LeetCode. Problems are matched by slug, so a solve on
+// leetcode.cn counts for a problem linked on leetcode.com.
+type Checker struct{}
+
+// ProblemID returns the slug of a problem
+func (Checker) ProblemID(problemURL string) string {
+	return SlugFromURL(problemURL)
+}
+
+// RecentAccepted lists the recent accepted submissions of a LeetCode account
+func (Checker) RecentAccepted(account judge.Account) ([]judge.Accepted, error) {
+	recent, err := GetRecentAC(Site(account.Site), account.Handle)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := make([]judge.Accepted, 0, len(recent))
+	for _, ac := range recent {
+		accepted = append(accepted, judge.Accepted{ProblemID: ac.TitleSlug, Title: ac.Title, At: ac.Timestamp})
+	}
+	return accepted, nil
+}
+
+// ProfileText returns the "Real name" and "About me" of a LeetCode account
+func (Checker) ProfileText(account judge.Account) (string, error) {
+	profile, err := GetUserProfile(Site(account.Site), account.Handle)
+	if err != nil {
+		return "", err
+	}
+	return profile.RealName + "\n" + profile.AboutMe, nil
+}
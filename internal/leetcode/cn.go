@@ -0,0 +1,136 @@
+package leetcode
+
+import (
+	"fmt"
+	"time"
+)
+
+// leetcode.cn serves profile data from its main GraphQL endpoint and submissions from
+// the noj-go one, with a different schema than leetcode.com
+const (
+	cnGraphQLEndpoint    = "https://leetcode.cn/graphql/"
+	cnNojGraphQLEndpoint = "https://leetcode.cn/graphql/noj-go/"
+)
+
+type cnRecentACResponse struct {
+	Data struct {
+		RecentACSubmissions []struct {
+			SubmitTime int64 `json:"submitTime"`
+			Question   struct {
+				Title           string `json:"title"`
+				TranslatedTitle string `json:"translatedTitle"`
+				TitleSlug       string `json:"titleSlug"`
+			} `json:"question"`
+		} `json:"recentACSubmissions"`
+	} `json:"data"`
+}
+
+type cnUserProfileResponse struct {
+	Data struct {
+		UserProfilePublicProfile *struct {
+			SiteRanking int `json:"siteRanking"`
+			Profile     struct {
+				UserSlug string `json:"userSlug"`
+				RealName string `json:"realName"`
+				AboutMe  string `json:"aboutMe"`
+			} `json:"profile"`
+		} `json:"userProfilePublicProfile"`
+		UserProfileUserQuestionProgress *struct {
+			NumAcceptedQuestions []struct {
+				Difficulty string `json:"difficulty"`
+				Count      int    `json:"count"`
+			} `json:"numAcceptedQuestions"`
+		} `json:"userProfileUserQuestionProgress"`
+		UserContestRanking *struct {
+			Rating                float64 `json:"rating"`
+			AttendedContestsCount int     `json:"attendedContestsCount"`
+		} `json:"userContestRanking"`
+	} `json:"data"`
+}
+
+// getRecentACCN fetches the recent accepted submissions of a leetcode.cn user
+func getRecentACCN(username string) ([]RecentAC, error) {
+	query := `
+    query recentAcSubmissions($userSlug: String!) {
+  recentACSubmissions(userSlug: $userSlug) {
+    submitTime
+    question {
+      title
+      translatedTitle
+      titleSlug
+    }
+  }
+}`
+	var result cnRecentACResponse
+	if err := postGraphQL(cnNojGraphQLEndpoint, "recentAcSubmissions", query, map[string]interface{}{"userSlug": username}, &result); err != nil {
+		return nil, fmt.Errorf("failed to get recent AC submissions for user %s: %w", username, err)
+	}
+
+	var recent []RecentAC
+	for _, ac := range result.Data.RecentACSubmissions {
+		recent = append(recent, RecentAC{
+			Title:     ac.Question.Title,
+			TitleSlug: ac.Question.TitleSlug,
+			Timestamp: time.Unix(ac.SubmitTime, 0),
+		})
+	}
+	return recent, nil
+}
+
+// getUserProfileCN fetches the public stats of a leetcode.cn user
+func getUserProfileCN(username string) (*UserProfile, error) {
+	query := `
+    query userProfile($userSlug: String!) {
+  userProfilePublicProfile(userSlug: $userSlug) {
+    siteRanking
+    profile {
+      userSlug
+      realName
+      aboutMe
+    }
+  }
+  userProfileUserQuestionProgress(userSlug: $userSlug) {
+    numAcceptedQuestions {
+      difficulty
+      count
+    }
+  }
+  userContestRanking(userSlug: $userSlug) {
+    rating
+    attendedContestsCount
+  }
+}`
+	var result cnUserProfileResponse
+	if err := postGraphQL(cnGraphQLEndpoint, "userProfile", query, map[string]interface{}{"userSlug": username}, &result); err != nil {
+		return nil, fmt.Errorf("failed to get profile of %s: %w", username, err)
+	}
+	if result.Data.UserProfilePublicProfile == nil {
+		return nil, fmt.Errorf("user %s not found", username)
+	}
+
+	u := result.Data.UserProfilePublicProfile
+	profile := &UserProfile{
+		Username: u.Profile.UserSlug,
+		RealName: u.Profile.RealName,
+		AboutMe:  u.Profile.AboutMe,
+		Ranking:  u.SiteRanking,
+	}
+	if progress := result.Data.UserProfileUserQuestionProgress; progress != nil {
+		for _, stat := range progress.NumAcceptedQuestions {
+			switch stat.Difficulty {
+			case "EASY":
+				profile.EasySolved = stat.Count
+			case "MEDIUM":
+				profile.MediumSolved = stat.Count
+			case "HARD":
+				profile.HardSolved = stat.Count
+			}
+		}
+		profile.TotalSolved = profile.EasySolved + profile.MediumSolved + profile.HardSolved
+	}
+	if ranking := result.Data.UserContestRanking; ranking != nil {
+		profile.ContestRating = ranking.Rating
+		profile.ContestsAttended = ranking.AttendedContestsCount
+	}
+	return profile, nil
+}
@@ -1,14 +1,23 @@
 package leetcode
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
+
+	"leetcode-telegram-bot/internal/metrics"
 )
 
+const graphQLEndpoint = "https://leetcode.com/graphql"
+
+// ErrQuestionNotFound is returned by GetQuestion when LeetCode has no problem with the slug
+var ErrQuestionNotFound = errors.New("question not found")
+
 type RecentACEntry struct {
 	Title     string `json:"title"`
 	TitleSlug string `json:"titleSlug"`
@@ -29,28 +38,137 @@ type RecentAC struct {
 	Timestamp time.Time
 }
 
-func queryRecentACList(username string) ([]RecentACEntry, error) {
-	// Call leetcode graphql api
-	query := fmt.Sprintf(`{"query":"\n    query recentAcSubmissions($username: String!, $limit: Int!) {\n  recentAcSubmissionList(username: $username, limit: $limit) {\n    id\n    title\n    titleSlug\n    timestamp\n  }\n}\n    ","variables":{"username":"%s","limit":15},"operationName":"recentAcSubmissions"}`, username)
-	resp, err := http.Post("https://leetcode.com/graphql", "application/json", strings.NewReader(query))
+// Question holds the public metadata of a LeetCode problem
+type Question struct {
+	Title      string
+	TitleSlug  string
+	Difficulty string
+	IsPaidOnly bool
+	TopicTags  []string
+}
+
+// UserProfile holds the public stats of a LeetCode user
+type UserProfile struct {
+	Username         string
+	RealName         string
+	AboutMe          string
+	Ranking          int
+	EasySolved       int
+	MediumSolved     int
+	HardSolved       int
+	TotalSolved      int
+	ContestRating    float64
+	ContestsAttended int
+	Badges           []string
+}
+
+type userProfileResponse struct {
+	Data struct {
+		MatchedUser *struct {
+			Username string `json:"username"`
+			Profile  struct {
+				RealName string `json:"realName"`
+				AboutMe  string `json:"aboutMe"`
+				Ranking  int    `json:"ranking"`
+			} `json:"profile"`
+			SubmitStatsGlobal struct {
+				AcSubmissionNum []struct {
+					Difficulty string `json:"difficulty"`
+					Count      int    `json:"count"`
+				} `json:"acSubmissionNum"`
+			} `json:"submitStatsGlobal"`
+			Badges []struct {
+				DisplayName string `json:"displayName"`
+			} `json:"badges"`
+		} `json:"matchedUser"`
+		UserContestRanking *struct {
+			Rating                float64 `json:"rating"`
+			AttendedContestsCount int     `json:"attendedContestsCount"`
+		} `json:"userContestRanking"`
+	} `json:"data"`
+}
+
+type questionEntry struct {
+	Title      string `json:"title"`
+	TitleSlug  string `json:"titleSlug"`
+	Difficulty string `json:"difficulty"`
+	IsPaidOnly bool   `json:"isPaidOnly"`
+	TopicTags  []struct {
+		Name string `json:"name"`
+	} `json:"topicTags"`
+}
+
+type questionResponse struct {
+	Data struct {
+		Question *questionEntry `json:"question"`
+	} `json:"data"`
+}
+
+// postGraphQL sends a GraphQL query to a LeetCode endpoint and decodes the response into
+// result. The latency and failures of each operation are recorded in the metrics.
+func postGraphQL(endpoint, operationName, query string, variables map[string]interface{}, result interface{}) error {
+	start := time.Now()
+	defer metrics.LeetCodeRequestDuration.ObserveSince(start, operationName)
+
+	if err := doGraphQL(endpoint, operationName, query, variables, result); err != nil {
+		metrics.LeetCodeRequestErrors.Inc(operationName)
+		return err
+	}
+	return nil
+}
+
+// doGraphQL sends a GraphQL query and decodes the response into result
+func doGraphQL(endpoint, operationName, query string, variables map[string]interface{}, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"operationName": operationName,
+		"query":         query,
+		"variables":     variables,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch recent AC submissions: %w", err)
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	resp, err := http.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to call leetcode: %w", err)
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch recent AC submissions: %s", resp.Status)
+		return fmt.Errorf("failed to call leetcode: %s", resp.Status)
 	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+func queryRecentACList(username string) ([]RecentACEntry, error) {
+	// Call leetcode graphql api
+	query := `
+    query recentAcSubmissions($username: String!, $limit: Int!) {
+  recentAcSubmissionList(username: $username, limit: $limit) {
+    id
+    title
+    titleSlug
+    timestamp
+  }
+}`
+	variables := map[string]interface{}{"username": username, "limit": 15}
 	var result RecentACListResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := postGraphQL(graphQLEndpoint, "recentAcSubmissions", query, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch recent AC submissions: %w", err)
 	}
 	return result.Data.RecentACSubmissionList, nil
 }
 
-func GetRecentACByUsername(username string) ([]RecentAC, error) {
+// GetRecentAC fetches the recent accepted submissions of a user on the given site
+func GetRecentAC(site Site, username string) ([]RecentAC, error) {
 	if username == "" {
 		return nil, fmt.Errorf("username cannot be empty")
 	}
+	if site == SiteChina {
+		return getRecentACCN(username)
+	}
 	recentACList, err := queryRecentACList(username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get recent AC submissions for user %s: %w", username, err)
@@ -71,3 +189,121 @@ func GetRecentACByUsername(username string) ([]RecentAC, error) {
 	return result, nil
 }
 
+// GetQuestion fetches the metadata of a problem by its slug
+func GetQuestion(slug string) (*Question, error) {
+	if slug == "" {
+		return nil, fmt.Errorf("slug cannot be empty")
+	}
+	query := `
+    query questionData($titleSlug: String!) {
+  question(titleSlug: $titleSlug) {
+    title
+    titleSlug
+    difficulty
+    isPaidOnly
+    topicTags {
+      name
+    }
+  }
+}`
+	var result questionResponse
+	if err := postGraphQL(graphQLEndpoint, "questionData", query, map[string]interface{}{"titleSlug": slug}, &result); err != nil {
+		return nil, fmt.Errorf("failed to get question %s: %w", slug, err)
+	}
+	if result.Data.Question == nil {
+		return nil, fmt.Errorf("question %s: %w", slug, ErrQuestionNotFound)
+	}
+
+	q := result.Data.Question
+	question := &Question{
+		Title:      q.Title,
+		TitleSlug:  q.TitleSlug,
+		Difficulty: q.Difficulty,
+		IsPaidOnly: q.IsPaidOnly,
+	}
+	for _, tag := range q.TopicTags {
+		question.TopicTags = append(question.TopicTags, tag.Name)
+	}
+	return question, nil
+}
+
+// GetUserProfile fetches the public stats of a user on the given site
+func GetUserProfile(site Site, username string) (*UserProfile, error) {
+	if username == "" {
+		return nil, fmt.Errorf("username cannot be empty")
+	}
+	if site == SiteChina {
+		return getUserProfileCN(username)
+	}
+	query := `
+    query userProfile($username: String!) {
+  matchedUser(username: $username) {
+    username
+    profile {
+      realName
+      aboutMe
+      ranking
+    }
+    submitStatsGlobal {
+      acSubmissionNum {
+        difficulty
+        count
+      }
+    }
+    badges {
+      displayName
+    }
+  }
+  userContestRanking(username: $username) {
+    rating
+    attendedContestsCount
+  }
+}`
+	var result userProfileResponse
+	if err := postGraphQL(graphQLEndpoint, "userProfile", query, map[string]interface{}{"username": username}, &result); err != nil {
+		return nil, fmt.Errorf("failed to get profile of %s: %w", username, err)
+	}
+	if result.Data.MatchedUser == nil {
+		return nil, fmt.Errorf("user %s not found", username)
+	}
+
+	u := result.Data.MatchedUser
+	profile := &UserProfile{
+		Username: u.Username,
+		RealName: u.Profile.RealName,
+		AboutMe:  u.Profile.AboutMe,
+		Ranking:  u.Profile.Ranking,
+	}
+	for _, stat := range u.SubmitStatsGlobal.AcSubmissionNum {
+		switch stat.Difficulty {
+		case "All":
+			profile.TotalSolved = stat.Count
+		case "Easy":
+			profile.EasySolved = stat.Count
+		case "Medium":
+			profile.MediumSolved = stat.Count
+		case "Hard":
+			profile.HardSolved = stat.Count
+		}
+	}
+	for _, badge := range u.Badges {
+		profile.Badges = append(profile.Badges, badge.DisplayName)
+	}
+	if ranking := result.Data.UserContestRanking; ranking != nil {
+		profile.ContestRating = ranking.Rating
+		profile.ContestsAttended = ranking.AttendedContestsCount
+	}
+	return profile, nil
+}
+
+// SlugFromURL extracts the problem slug from a problem URL,
+// e.g. "two-sum" from https://leetcode.com/problems/two-sum/
+func SlugFromURL(url string) string {
+	parts := strings.Split(strings.Trim(url, "/"), "/")
+	for i, part := range parts {
+		if part == "problems" && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
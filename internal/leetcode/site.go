@@ -0,0 +1,78 @@
+package leetcode
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Site is the LeetCode site an account belongs to
+type Site string
+
+const (
+	SiteGlobal Site = "leetcode.com"
+	SiteChina  Site = "leetcode.cn"
+)
+
+// ParseSite parses a site name such as "com", "cn" or "leetcode.cn"
+func ParseSite(value string) (Site, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "com", "leetcode.com":
+		return SiteGlobal, nil
+	case "cn", "leetcode.cn", "leetcode-cn.com":
+		return SiteChina, nil
+	default:
+		return "", fmt.Errorf("unknown LeetCode site %q", value)
+	}
+}
+
+// SiteFromURL returns the site a problem URL points to, leetcode.com unless it is on leetcode.cn
+func SiteFromURL(problemURL string) Site {
+	u, err := url.Parse(problemURL)
+	if err != nil {
+		return SiteGlobal
+	}
+	if site, err := ParseSite(strings.TrimPrefix(u.Hostname(), "www.")); err == nil {
+		return site
+	}
+	return SiteGlobal
+}
+
+// BaseURL returns the address of the site, e.g. https://leetcode.com
+func (s Site) BaseURL() string {
+	if s == "" {
+		return "https://" + string(SiteGlobal)
+	}
+	return "https://" + string(s)
+}
+
+// ProblemURL returns the address of a problem on the site
+func (s Site) ProblemURL(slug string) string {
+	return fmt.Sprintf("%s/problems/%s/", s.BaseURL(), slug)
+}
+
+// DiscussURL returns the address of the discussions of a problem. leetcode.cn has no
+// discussions, its members share solutions instead.
+func (s Site) DiscussURL(slug string) string {
+	if s == SiteChina {
+		return s.ProblemURL(slug) + "solutions/"
+	}
+	return s.ProblemURL(slug) + "discuss/"
+}
+
+// EditorialURL returns the address of the official solution of a problem. leetcode.cn
+// lists it first among the shared solutions.
+func (s Site) EditorialURL(slug string) string {
+	if s == SiteChina {
+		return s.ProblemURL(slug) + "solutions/"
+	}
+	return s.ProblemURL(slug) + "editorial/"
+}
+
+// ProfileSettingsURL returns the address where users edit their public profile
+func (s Site) ProfileSettingsURL() string {
+	if s == SiteChina {
+		return s.BaseURL() + "/profile/info/"
+	}
+	return s.BaseURL() + "/profile/"
+}
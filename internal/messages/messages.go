@@ -0,0 +1,328 @@
+package messages
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"leetcode-telegram-bot/internal/achievements"
+	"leetcode-telegram-bot/internal/models"
+)
+
+// builtin holds the default templates, one directory per language
+//
+//go:embed templates
+var builtin embed.FS
+
+// DefaultLanguage is the language every template exists in. Templates missing from
+// other languages fall back to it.
+const DefaultLanguage = "en"
+
+// Names of the message templates
+const (
+	DailyChallenge = "daily_challenge"
+	Reminder       = "reminder"
+	Leaderboard    = "leaderboard"
+	Solved         = "solved"
+	AlreadySolved  = "already_solved"
+	Help           = "help"
+	ChatHelp       = "chat_help"
+	DailyDigest    = "daily_digest"
+	WeeklyDigest   = "weekly_digest"
+	Achievements   = "achievements"
+)
+
+// Names lists every message template
+var Names = []string{DailyChallenge, Reminder, Leaderboard, Solved, AlreadySolved, Help, ChatHelp,
+	DailyDigest, WeeklyDigest, Achievements}
+
+// funcs are the functions templates can use besides the text/template builtins
+var funcs = template.FuncMap{
+	"join":   strings.Join,
+	"repeat": strings.Repeat,
+}
+
+// DailyChallengeData is the data of the daily_challenge template
+type DailyChallengeData struct {
+	DayNumber int
+	Date      time.Time
+	Problem   *models.Problem
+	Judge     string        // Name of the judge, empty for LeetCode
+	Track     *models.Track // Active track, nil when there is none
+	Solvers   []string      // First names of the members who solved it so far
+	Platform  string        // Chat platform the message is for, e.g. "telegram"
+}
+
+// ReminderData is the data of the reminder template
+type ReminderData struct {
+	Evening   bool
+	Mentions  []string
+	DayNumber int
+	Problem   *models.Problem
+	Platform  string
+}
+
+// LeaderboardData is the data of the leaderboard template
+type LeaderboardData struct {
+	Entries    []LeaderboardEntry
+	ShowPoints bool // Whether the hint penalty is on, making points differ from solves
+}
+
+// LeaderboardEntry is a line of the leaderboard
+type LeaderboardEntry struct {
+	Rank   string // Medal or rank number
+	Name   string
+	Solved int
+	Points float64
+}
+
+// SolvedData is the data of the solved template
+type SolvedData struct {
+	Verified bool
+	Judge    string
+}
+
+// HelpData is the data of the help template
+type HelpData struct {
+	SolutionRevealTime string
+}
+
+// DailyDigestData is the data of the daily_digest template
+type DailyDigestData struct {
+	DayNumber  int
+	Problem    *models.Problem
+	Solved     []DigestSolver
+	Missed     []string          // First names of the members who didn't solve it
+	SolveTimes []SolveTimeBucket // Distribution of the time to solve, empty when nobody solved it
+	Next       NextChallenge
+}
+
+// DigestSolver is a member who solved the challenge of a daily digest
+type DigestSolver struct {
+	Name     string
+	Verified bool
+}
+
+// SolveTimeBucket is a bar of the solve-time distribution, e.g. "1-3h"
+type SolveTimeBucket struct {
+	Label string
+	Count int
+}
+
+// NextChallenge describes what will be posted on the next challenge day
+type NextChallenge struct {
+	Date         time.Time
+	Cancelled    bool
+	Problem      *models.Problem // Planned problem, nil when none is queued
+	Track        *models.Track   // Track the problem comes from when none is queued, or nil
+	TrackProblem int             // Position of that problem in the track
+}
+
+// WeeklyDigestData is the data of the weekly_digest template
+type WeeklyDigestData struct {
+	From          time.Time
+	To            time.Time
+	Challenges    []models.ChallengeHistoryEntry
+	TopSolvers    []LeaderboardEntry
+	ShowPoints    bool // Whether the hint penalty is on, making points differ from solves
+	Solves        int
+	Members       int
+	Participation int // Percentage of the possible solves that happened
+}
+
+// AchievementsData is the data of the achievements template
+type AchievementsData struct {
+	Name         string
+	Achievements []achievements.Achievement
+}
+
+// Catalog holds the message templates of every language. Templates in the override
+// directory replace the built-in ones with the same language and name.
+type Catalog struct {
+	dir string
+
+	mu         sync.RWMutex
+	builtin    map[string]map[string]*template.Template
+	templates  map[string]map[string]*template.Template
+	overridden []string
+}
+
+// Load parses the built-in templates and the overrides in dir, if it is not empty
+func Load(dir string) (*Catalog, error) {
+	defaults, err := parseBuiltin()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Catalog{dir: dir, builtin: defaults}
+	if err := c.Reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload parses the override directory again. On error the templates in use are kept.
+func (c *Catalog) Reload() error {
+	templates := make(map[string]map[string]*template.Template)
+	for language, byName := range c.builtin {
+		templates[language] = make(map[string]*template.Template)
+		for name, tmpl := range byName {
+			templates[language][name] = tmpl
+		}
+	}
+
+	var overridden []string
+	if c.dir != "" {
+		files, err := filepath.Glob(filepath.Join(c.dir, "*", "*.tmpl"))
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			language := filepath.Base(filepath.Dir(file))
+			name := strings.TrimSuffix(filepath.Base(file), ".tmpl")
+			if !known(name) {
+				return fmt.Errorf("unknown message template %s", file)
+			}
+
+			text, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			tmpl, err := parse(language+"/"+name, string(text))
+			if err != nil {
+				return err
+			}
+
+			if templates[language] == nil {
+				templates[language] = make(map[string]*template.Template)
+			}
+			templates[language][name] = tmpl
+			overridden = append(overridden, language+"/"+name)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.templates = templates
+	c.overridden = overridden
+	return nil
+}
+
+// Languages returns the languages that have templates, sorted
+func (c *Catalog) Languages() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	languages := make([]string, 0, len(c.templates))
+	for language := range c.templates {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// HasLanguage reports whether there are templates in the given language
+func (c *Catalog) HasLanguage(language string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.templates[language]
+	return ok
+}
+
+// Overridden returns the templates loaded from the override directory, as language/name
+func (c *Catalog) Overridden() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string(nil), c.overridden...)
+}
+
+// Render executes a template in the given language. If the template is missing or fails,
+// e.g. because an override uses a field that doesn't exist, the built-in template of the
+// language is used instead, and then the English one.
+func (c *Catalog) Render(language, name string, data interface{}) string {
+	c.mu.RLock()
+	candidates := []*template.Template{
+		c.templates[language][name],
+		c.builtin[language][name],
+		c.builtin[DefaultLanguage][name],
+	}
+	c.mu.RUnlock()
+
+	for _, tmpl := range candidates {
+		if tmpl == nil {
+			continue
+		}
+		var text strings.Builder
+		if err := tmpl.Execute(&text, data); err != nil {
+			log.Printf("Error rendering message template %s: %v", tmpl.Name(), err)
+			continue
+		}
+		return strings.TrimSpace(text.String())
+	}
+
+	log.Printf("Message template %s is missing", name)
+	return ""
+}
+
+// parseBuiltin parses the embedded templates
+func parseBuiltin() (map[string]map[string]*template.Template, error) {
+	templates := make(map[string]map[string]*template.Template)
+
+	files, err := fs.Glob(builtin, "templates/*/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		language := path.Base(path.Dir(file))
+		name := strings.TrimSuffix(path.Base(file), ".tmpl")
+
+		text, err := builtin.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := parse(language+"/"+name, string(text))
+		if err != nil {
+			return nil, err
+		}
+
+		if templates[language] == nil {
+			templates[language] = make(map[string]*template.Template)
+		}
+		templates[language][name] = tmpl
+	}
+
+	for _, name := range Names {
+		if templates[DefaultLanguage][name] == nil {
+			return nil, fmt.Errorf("built-in message template %s/%s is missing", DefaultLanguage, name)
+		}
+	}
+	return templates, nil
+}
+
+// parse parses the text of a template
+func parse(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// known reports whether name is one of the message templates
+func known(name string) bool {
+	for _, known := range Names {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
@@ -6,11 +6,13 @@ import (
 
 // Problem represents a LeetCode problem
 type Problem struct {
-	ID       int    `json:"id" db:"id"`
-	Title    string `json:"title" db:"title"`
-	URL      string `json:"url" db:"url"`
-	Category string `json:"category" db:"category"`
-	Used     bool   `json:"used" db:"used"`
+	ID         int    `json:"id" db:"id"`
+	Title      string `json:"title" db:"title"`
+	URL        string `json:"url" db:"url"`
+	Category   string `json:"category" db:"category"`
+	Difficulty string `json:"difficulty" db:"difficulty"` // Easy, Medium or Hard; empty until synced from LeetCode
+	Platform   string `json:"platform" db:"platform"`     // Judge the problem is on, e.g. leetcode or codeforces
+	Used       bool   `json:"used" db:"used"`
 }
 
 // User represents a Telegram user
@@ -24,10 +26,70 @@ type User struct {
 
 // UserLeetcodeProfile represents a user's LeetCode profile
 type UserLeetcodeProfile struct {
-	ID          int64     `json:"id" db:"id"`
-	UserId		 int64     `json:"user_id" db:"user_id"`
+	ID               int64     `json:"id" db:"id"`
+	UserId           int64     `json:"user_id" db:"user_id"`
 	LeetCodeUsername string    `json:"leetcode_username" db:"leetcode_username"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	Site             string    `json:"site" db:"site"` // leetcode.com or leetcode.cn
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+
+	// The link is verified once the token shows up on the LeetCode profile
+	Verified          bool   `json:"verified" db:"verified"`
+	VerificationToken string `json:"-" db:"verification_token"`
+}
+
+// JudgeAccount represents a user's account on a judge other than LeetCode
+type JudgeAccount struct {
+	UserID            int64     `json:"user_id" db:"user_id"`
+	Platform          string    `json:"platform" db:"platform"`
+	Handle            string    `json:"handle" db:"handle"`
+	Verified          bool      `json:"verified" db:"verified"`
+	VerificationToken string    `json:"-" db:"verification_token"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+}
+
+// OutboundWebhook represents a URL that receives challenge events
+type OutboundWebhook struct {
+	ID        int       `json:"id" db:"id"`
+	URL       string    `json:"url" db:"url"`
+	Secret    string    `json:"-" db:"secret"`      // Key of the HMAC signature sent with each delivery
+	Events    []string  `json:"events" db:"events"` // Subscribed events, "*" for all of them
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// WebhookDelivery represents an event waiting in the queue to be delivered to a webhook
+type WebhookDelivery struct {
+	ID        int    `json:"id" db:"id"`
+	WebhookID int    `json:"webhook_id" db:"webhook_id"`
+	URL       string `json:"url"`
+	Secret    string `json:"-"`
+	Event     string `json:"event" db:"event"`
+	Payload   string `json:"payload" db:"payload"`
+	Attempts  int    `json:"attempts" db:"attempts"`
+}
+
+// ProfileSnapshot represents a user's public LeetCode stats at the time of a sync
+type ProfileSnapshot struct {
+	ID               int64     `json:"id" db:"id"`
+	UserID           int64     `json:"user_id" db:"user_id"`
+	LeetCodeUsername string    `json:"leetcode_username" db:"leetcode_username"`
+	EasySolved       int       `json:"easy_solved" db:"easy_solved"`
+	MediumSolved     int       `json:"medium_solved" db:"medium_solved"`
+	HardSolved       int       `json:"hard_solved" db:"hard_solved"`
+	TotalSolved      int       `json:"total_solved" db:"total_solved"`
+	Ranking          int       `json:"ranking" db:"ranking"`
+	ContestRating    float64   `json:"contest_rating" db:"contest_rating"`
+	ContestsAttended int       `json:"contests_attended" db:"contests_attended"`
+	Badges           []string  `json:"badges" db:"badges"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+}
+
+// ProfileImprovement represents how much a user's LeetCode stats grew over a period
+type ProfileImprovement struct {
+	UserID           int64   `json:"user_id"`
+	FirstName        string  `json:"first_name"`
+	LeetCodeUsername string  `json:"leetcode_username"`
+	SolvedGain       int     `json:"solved_gain"`
+	RatingGain       float64 `json:"rating_gain"`
 }
 
 // Submission represents a user's submission for a daily challenge
@@ -36,7 +98,11 @@ type Submission struct {
 	UserID      int64     `json:"user_id" db:"user_id"`
 	ProblemID   int       `json:"problem_id" db:"problem_id"`
 	SubmittedAt time.Time `json:"submitted_at" db:"submitted_at"`
-	Date        string    `json:"date" db:"date"` // Format: YYYY-MM-DD
+	Date        string    `json:"date" db:"date"`         // Format: YYYY-MM-DD
+	Verified    bool      `json:"verified" db:"verified"` // Confirmed on LeetCode rather than self-reported
+
+	// Accepted time on LeetCode, only known for verified submissions
+	SolvedAt *time.Time `json:"solved_at,omitempty" db:"solved_at"`
 }
 
 // DailyChallenge represents the daily challenge posted
@@ -46,21 +112,148 @@ type DailyChallenge struct {
 	Date      string    `json:"date" db:"date"` // Format: YYYY-MM-DD
 	PostedAt  time.Time `json:"posted_at" db:"posted_at"`
 	DayNumber int       `json:"day_number" db:"day_number"` // Day counter (starting from 9)
+	MessageID int       `json:"message_id" db:"message_id"` // Telegram message ID of the announcement
+
+	BoardMessageID int  `json:"board_message_id" db:"board_message_id"` // Telegram message ID of the pinned progress board
+	BoardFinalized bool `json:"board_finalized" db:"board_finalized"`   // Whether the end-of-day summary has been written
+}
+
+// QueueEntry represents a problem planned ahead of time by an admin
+type QueueEntry struct {
+	ID            int       `json:"id" db:"id"`
+	ProblemID     int       `json:"problem_id" db:"problem_id"`
+	Title         string    `json:"title"`
+	URL           string    `json:"url"`
+	ScheduledDate string    `json:"scheduled_date" db:"scheduled_date"` // Format: YYYY-MM-DD, empty for the next free day
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// SolveEntry represents a user's solve of a daily challenge
+type SolveEntry struct {
+	UserID    int64     `json:"user_id"`
+	Username  string    `json:"username"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	SolvedAt  time.Time `json:"solved_at"`
+	Verified  bool      `json:"verified"`
+
+	// Seconds from the post to the accepted submission, nil when the accepted time is unknown
+	SolveSeconds *int64 `json:"solve_seconds,omitempty"`
+}
+
+// SpeedEntry represents a user's solving speed on the speed leaderboard
+type SpeedEntry struct {
+	UserID         int64  `json:"user_id"`
+	Username       string `json:"username"`
+	FirstName      string `json:"first_name"`
+	LastName       string `json:"last_name"`
+	Solves         int    `json:"solves"`
+	AverageSeconds int64  `json:"average_seconds"`
+	BestSeconds    int64  `json:"best_seconds"`
+}
+
+// ChallengeHistoryEntry represents a past daily challenge with its problem and number of solvers
+type ChallengeHistoryEntry struct {
+	DayNumber int       `json:"day_number"`
+	Date      string    `json:"date"`
+	PostedAt  time.Time `json:"posted_at"`
+	ProblemID int       `json:"problem_id"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	Category  string    `json:"category"`
+	Solvers   int       `json:"solvers"`
+}
+
+// Solution represents a user's shared solution for a daily challenge
+type Solution struct {
+	ID        int       `json:"id" db:"id"`
+	UserID    int64     `json:"user_id" db:"user_id"`
+	FirstName string    `json:"first_name"`
+	ProblemID int       `json:"problem_id" db:"problem_id"`
+	Date      string    `json:"date" db:"date"` // Format: YYYY-MM-DD
+	DayNumber int       `json:"day_number"`
+	Language  string    `json:"language" db:"language"`
+	Code      string    `json:"code" db:"code"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	Revealed  bool      `json:"revealed" db:"revealed"` // Whether it has been posted to the group
 }
 
 // LeaderboardEntry represents a user's statistics for leaderboard
 type LeaderboardEntry struct {
-	UserID      int64  `json:"user_id"`
-	Username    string `json:"username"`
-	FirstName   string `json:"first_name"`
-	LastName    string `json:"last_name"`
-	TotalSolved int    `json:"total_solved"`
+	UserID      int64   `json:"user_id"`
+	Username    string  `json:"username"`
+	FirstName   string  `json:"first_name"`
+	LastName    string  `json:"last_name"`
+	TotalSolved int     `json:"total_solved"`
+	Points      float64 `json:"points"`
 }
 
 // ProblemsData represents the structure of the YAML file
 type ProblemsData map[string][]struct {
-	Title string `yaml:"title"`
-	URL   string `yaml:"url"`
+	Title    string `yaml:"title"`
+	URL      string `yaml:"url"`
+	Platform string `yaml:"platform"` // Optional, detected from the URL when empty
+}
+
+// TracksData represents the structure of the tracks YAML file
+type TracksData []struct {
+	Name     string `yaml:"name"`
+	Start    string `yaml:"start"` // Optional, format: YYYY-MM-DD
+	End      string `yaml:"end"`   // Optional, format: YYYY-MM-DD
+	Problems []struct {
+		Title    string `yaml:"title"`
+		URL      string `yaml:"url"`
+		Category string `yaml:"category"`
+		Platform string `yaml:"platform"` // Optional, detected from the URL when empty
+	} `yaml:"problems"`
+}
+
+// Track represents a curated, ordered list of problems that can be turned on for a date range
+type Track struct {
+	ID        int    `json:"id" db:"id"`
+	Name      string `json:"name" db:"name"`
+	StartDate string `json:"start_date" db:"start_date"` // Format: YYYY-MM-DD, empty when not scheduled
+	EndDate   string `json:"end_date" db:"end_date"`     // Format: YYYY-MM-DD, empty when not scheduled
+	Total     int    `json:"total"`
+	Posted    int    `json:"posted"`
+}
+
+// PracticeProblem represents a problem handed out to a user for personal practice
+type PracticeProblem struct {
+	ID         int        `json:"id" db:"id"`
+	UserID     int64      `json:"user_id" db:"user_id"`
+	ProblemID  int        `json:"problem_id" db:"problem_id"`
+	Title      string     `json:"title"`
+	URL        string     `json:"url"`
+	Category   string     `json:"category"`
+	Difficulty string     `json:"difficulty"`
+	AssignedAt time.Time  `json:"assigned_at" db:"assigned_at"`
+	SolvedAt   *time.Time `json:"solved_at,omitempty" db:"solved_at"`
+}
+
+// PracticeStats represents a user's personal practice statistics, kept apart from the group leaderboard
+type PracticeStats struct {
+	Assigned     int            `json:"assigned"`
+	Solved       int            `json:"solved"`
+	ByDifficulty map[string]int `json:"by_difficulty"`
+	ByCategory   map[string]int `json:"by_category"`
+}
+
+// UserStats represents a user's daily challenge statistics
+type UserStats struct {
+	User                User           `json:"user"`
+	TotalSolved         int            `json:"total_solved"`
+	Verified            int            `json:"verified"`
+	SelfReported        int            `json:"self_reported"`
+	CurrentStreak       int            `json:"current_streak"`
+	LongestStreak       int            `json:"longest_streak"`
+	ByCategory          map[string]int `json:"by_category"`
+	CategoriesSolved    int            `json:"categories_solved"`
+	AverageSolveSeconds int64          `json:"average_solve_seconds"`
+	ChallengesSinceJoin int            `json:"challenges_since_join"`
+	SolvedSinceJoin     int            `json:"solved_since_join"`
+	ParticipationRate   float64        `json:"participation_rate"`
+	Achievements        []string       `json:"achievements"`
 }
 
 // ChallengeCounter represents the global challenge counter
@@ -1,8 +1,11 @@
 package scheduler
 
 import (
+	"errors"
 	"io/ioutil"
 	"log"
+	"os"
+	"sync/atomic"
 	"time"
 
 	"leetcode-telegram-bot/internal/bot"
@@ -20,6 +23,8 @@ type Scheduler struct {
 	bot    *bot.Bot
 	db     *database.DB
 	config *config.Config
+
+	running atomic.Bool
 }
 
 // New creates a new scheduler instance
@@ -47,11 +52,14 @@ func (s *Scheduler) Start() {
 	if err := s.loadProblemsFromFile(); err != nil {
 		log.Printf("Warning: Failed to load problems from file: %v", err)
 	}
+	if err := s.loadTracksFromFile(); err != nil {
+		log.Printf("Warning: Failed to load tracks from file: %v", err)
+	}
 
 	// Schedule daily challenge posting at 7:00 AM, Monday to Friday only
 	_, err := s.cron.AddFunc("0 7 * * 1-5", func() {
 		log.Println("Posting daily challenge...")
-		if err := s.bot.PostDailyChallenge(); err != nil {
+		if err := s.bot.PostDailyChallenge(); err != nil && !errors.Is(err, bot.ErrDaySkipped) {
 			log.Printf("Error posting daily challenge: %v", err)
 		}
 	})
@@ -81,6 +89,49 @@ func (s *Scheduler) Start() {
 		log.Printf("Error scheduling evening reminder: %v", err)
 	}
 
+	// Schedule progress board finalization at 11:55 PM, Monday to Friday only
+	_, err = s.cron.AddFunc("55 23 * * 1-5", func() {
+		log.Println("Finalizing progress board...")
+		if err := s.bot.FinalizeProgressBoard(); err != nil {
+			log.Printf("Error finalizing progress board: %v", err)
+		}
+	})
+	if err != nil {
+		log.Printf("Error scheduling progress board finalization: %v", err)
+	}
+
+	// Tell outbound webhooks about streaks that ended today, before the day is over
+	_, err = s.cron.AddFunc("50 23 * * 1-5", func() {
+		if err := s.bot.PublishBrokenStreaks(); err != nil {
+			log.Printf("Error publishing broken streaks: %v", err)
+		}
+	})
+	if err != nil {
+		log.Printf("Error scheduling broken streak events: %v", err)
+	}
+
+	// Schedule the end-of-day digest
+	_, err = s.cron.AddFunc(s.config.DailyDigestSchedule, func() {
+		log.Println("Sending daily digest...")
+		if err := s.bot.SendDailyDigest(); err != nil {
+			log.Printf("Error sending daily digest: %v", err)
+		}
+	})
+	if err != nil {
+		log.Printf("Error scheduling daily digest: %v", err)
+	}
+
+	// Schedule the end-of-week digest
+	_, err = s.cron.AddFunc(s.config.WeeklyDigestSchedule, func() {
+		log.Println("Sending weekly digest...")
+		if err := s.bot.SendWeeklyDigest(); err != nil {
+			log.Printf("Error sending weekly digest: %v", err)
+		}
+	})
+	if err != nil {
+		log.Printf("Error scheduling weekly digest: %v", err)
+	}
+
 	// Schedule check submissions every 5 minutes
 	_, err = s.cron.AddFunc("*/5 * * * *", func() {
 		log.Println("Checking new submissions...")
@@ -92,17 +143,79 @@ func (s *Scheduler) Start() {
 		log.Printf("Error scheduling check new submissions: %v", err)
 	}
 
+	// Schedule practice checks every 30 minutes
+	_, err = s.cron.AddFunc("*/30 * * * *", func() {
+		log.Println("Checking practice submissions...")
+		if err := s.bot.CheckPracticeSubmissions(); err != nil {
+			log.Printf("Error while checking practice submissions: %v", err)
+		}
+	})
+	if err != nil {
+		log.Printf("Error scheduling practice checks: %v", err)
+	}
+
+	// Sync missing problem difficulties from LeetCode every hour, a batch at a time
+	_, err = s.cron.AddFunc("15 * * * *", func() {
+		if err := s.bot.SyncProblemDifficulties(25); err != nil {
+			log.Printf("Error syncing problem difficulties: %v", err)
+		}
+	})
+	if err != nil {
+		log.Printf("Error scheduling problem difficulty sync: %v", err)
+	}
+
+	// Reveal shared solutions every 5 minutes once the reveal time has passed
+	_, err = s.cron.AddFunc("*/5 * * * *", func() {
+		if err := s.bot.RevealSolutions(); err != nil {
+			log.Printf("Error revealing solutions: %v", err)
+		}
+	})
+	if err != nil {
+		log.Printf("Error scheduling solution reveal: %v", err)
+	}
+
+	// Snapshot the public LeetCode stats of registered users every night
+	_, err = s.cron.AddFunc("0 3 * * *", func() {
+		log.Println("Syncing LeetCode profiles...")
+		if err := s.bot.SyncLeetcodeProfiles(); err != nil {
+			log.Printf("Error syncing LeetCode profiles: %v", err)
+		}
+	})
+	if err != nil {
+		log.Printf("Error scheduling LeetCode profile sync: %v", err)
+	}
+
+	// Deliver queued events to outbound webhooks every minute
+	_, err = s.cron.AddFunc("* * * * *", func() {
+		if err := s.bot.DeliverWebhooks(); err != nil {
+			log.Printf("Error delivering webhooks: %v", err)
+		}
+	})
+	if err != nil {
+		log.Printf("Error scheduling webhook deliveries: %v", err)
+	}
+
 	// Start the cron scheduler
 	s.cron.Start()
+	s.running.Store(true)
 	log.Println("Scheduler started successfully - posting challenges Monday to Friday only")
 }
 
 // Stop stops the scheduler
 func (s *Scheduler) Stop() {
+	s.running.Store(false)
 	s.cron.Stop()
 	log.Println("Scheduler stopped")
 }
 
+// Ready checks that the cron jobs are running
+func (s *Scheduler) Ready() error {
+	if !s.running.Load() {
+		return errors.New("scheduler is not running")
+	}
+	return nil
+}
+
 // loadProblemsFromFile loads problems from the YAML file into the database
 func (s *Scheduler) loadProblemsFromFile() error {
 	// Read the YAML file
@@ -126,6 +239,30 @@ func (s *Scheduler) loadProblemsFromFile() error {
 	return nil
 }
 
+// loadTracksFromFile loads curated tracks from the YAML file into the database.
+// The tracks file is optional.
+func (s *Scheduler) loadTracksFromFile() error {
+	data, err := ioutil.ReadFile(s.config.TracksFilePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var tracksData models.TracksData
+	if err := yaml.Unmarshal(data, &tracksData); err != nil {
+		return err
+	}
+
+	if err := s.db.LoadTracksFromYAML(tracksData); err != nil {
+		return err
+	}
+
+	log.Printf("Successfully loaded %d tracks from %s", len(tracksData), s.config.TracksFilePath)
+	return nil
+}
+
 // GetNextScheduledTimes returns information about next scheduled tasks (for debugging)
 func (s *Scheduler) GetNextScheduledTimes() []time.Time {
 	entries := s.cron.Entries()
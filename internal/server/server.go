@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"leetcode-telegram-bot/internal/metrics"
+)
+
+// Server serves the bot's HTTP endpoints: health checks, metrics and, in webhook mode,
+// the Telegram webhook
+type Server struct {
+	mux    *http.ServeMux
+	server *http.Server
+	checks []readinessCheck
+}
+
+// readinessCheck is a dependency that must be healthy for the bot to be ready
+type readinessCheck struct {
+	name  string
+	check func() error
+}
+
+// New creates a server listening on addr with /healthz, /readyz and /metrics
+func New(addr string) *Server {
+	s := &Server{mux: http.NewServeMux()}
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	s.mux.HandleFunc("/healthz", s.handleHealthz)
+	s.mux.HandleFunc("/readyz", s.handleReadyz)
+	s.mux.Handle("/metrics", metrics.Handler())
+	return s
+}
+
+// Handle registers a handler for the given pattern
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// AddCheck adds a dependency checked by /readyz
+func (s *Server) AddCheck(name string, check func() error) {
+	s.checks = append(s.checks, readinessCheck{name: name, check: check})
+}
+
+// ListenAndServe serves requests until the server is shut down
+func (s *Server) ListenAndServe() error {
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops the server once in-flight requests are done
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// handleHealthz reports that the process is up
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintln(w, "ok")
+}
+
+// handleReadyz reports whether every dependency is healthy, listing each of them
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	var report strings.Builder
+	ready := true
+	for _, c := range s.checks {
+		if err := c.check(); err != nil {
+			ready = false
+			fmt.Fprintf(&report, "%s: %v\n", c.name, err)
+			continue
+		}
+		fmt.Fprintf(&report, "%s: ok\n", c.name)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	fmt.Fprint(w, report.String())
+}
@@ -6,11 +6,15 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"leetcode-telegram-bot/internal/api"
 	"leetcode-telegram-bot/internal/bot"
 	"leetcode-telegram-bot/internal/config"
+	"leetcode-telegram-bot/internal/dashboard"
 	"leetcode-telegram-bot/internal/database"
 	"leetcode-telegram-bot/internal/scheduler"
+	"leetcode-telegram-bot/internal/server"
 )
 
 func main() {
@@ -36,12 +40,40 @@ func main() {
 	// Initialize scheduler
 	scheduler := scheduler.New(telegramBot, db, cfg)
 
+	// Serve health checks, metrics, the webhook, the dashboard and the API
+	httpServer := server.New(cfg.HTTPAddr)
+	httpServer.AddCheck("database", db.Ping)
+	httpServer.AddCheck("telegram", telegramBot.Ready)
+	httpServer.AddCheck("scheduler", scheduler.Ready)
+	telegramBot.RegisterHandlers(httpServer)
+	if cfg.DashboardEnabled {
+		webDashboard, err := dashboard.New(db, cfg)
+		if err != nil {
+			log.Fatal("Failed to initialize dashboard:", err)
+		}
+		webDashboard.RegisterHandlers(httpServer)
+	}
+	if cfg.APIToken != "" || cfg.APIAdminToken != "" {
+		api.New(db, telegramBot, cfg).RegisterHandlers(httpServer)
+	}
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil {
+			log.Fatal("Failed to start HTTP server:", err)
+		}
+	}()
+
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Start bot
-	go telegramBot.Start(ctx)
+	botDone := make(chan struct{})
+	go func() {
+		defer close(botDone)
+		if err := telegramBot.Start(ctx); err != nil {
+			log.Fatal("Failed to start bot:", err)
+		}
+	}()
 
 	// Start scheduler
 	go scheduler.Start()
@@ -56,4 +88,13 @@ func main() {
 	log.Println("Shutting down...")
 	cancel()
 	scheduler.Stop()
+
+	// Wait for the bot to stop receiving updates and remove its webhook
+	<-botDone
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error shutting down HTTP server: %v", err)
+	}
 } 
\ No newline at end of file